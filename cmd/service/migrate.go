@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"strconv"
+)
+
+func runMigrate(args []string) (err error) {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+
+	cfg, err := loadConfig(flags, args)
+	if err != nil {
+		return fmt.Errorf("loadConfig: %w", err)
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		return errors.New("migrate command is missing")
+	}
+
+	command, args := args[0], args[1:]
+
+	// the arguments are validated before connecting to the database
+	var version int
+	switch command {
+	case "up", "down", "version":
+		if len(args) != 0 {
+			return fmt.Errorf("migrate %s takes no arguments", command)
+		}
+	case "goto", "force":
+		if len(args) != 1 {
+			return fmt.Errorf("migrate %s takes a version", command)
+		}
+
+		// force -1 resets the database to no version, e.g. after a dirty first migration
+		minVersion := 0
+		if command == "force" {
+			minVersion = -1
+		}
+
+		version, err = strconv.Atoi(args[0])
+		if err != nil || version < minVersion {
+			return fmt.Errorf("invalid version: %s", args[0])
+		}
+	default:
+		return fmt.Errorf("unknown migrate command: %s", command)
+	}
+
+	m, err := repository.NewMigrate(cfg.Database.URL)
+	if err != nil {
+		return fmt.Errorf("repository.NewMigrate: %w", err)
+	}
+	defer func() {
+		srcErr, dbErr := m.Close()
+		err = errors.Join(err, srcErr, dbErr)
+	}()
+
+	var op string
+	switch command {
+	case "up":
+		op, err = "m.Up", m.Up()
+	case "down":
+		op, err = "m.Down", m.Down()
+	case "goto":
+		op, err = "m.Migrate", m.Migrate(uint(version))
+	case "force":
+		op, err = "m.Force", m.Force(version)
+	case "version":
+		return printVersion(m)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return printVersion(m)
+}
+
+func printVersion(m *migrate.Migrate) error {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Println("no migrations applied")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("m.Version: %w", err)
+	}
+
+	fmt.Printf("version %d, dirty %t\n", version, dirty)
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nikolayk812/go-tests/internal/auth"
+	"github.com/nikolayk812/go-tests/internal/config"
+	"github.com/nikolayk812/go-tests/internal/lifecycle"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+)
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	skipMigrate := flags.Bool("skip-migrate", false, "do not migrate the database up on startup, "+
+		"when migrations run as a separate deployment step")
+
+	cfg, err := loadConfig(flags, args)
+	if err != nil {
+		return fmt.Errorf("loadConfig: %w", err)
+	}
+
+	if err := cfg.ValidateServe(); err != nil {
+		return fmt.Errorf("cfg.ValidateServe: %w", err)
+	}
+
+	gin.SetMode(cfg.Server.GinMode)
+	gin.DebugPrintRouteFunc = func(method, path, handler string, _ int) {
+		slog.Debug("route registered", "method", method, "path", path, "handler", handler)
+	}
+
+	if !*skipMigrate {
+		if err := repository.MigrateUp(cfg.Database.URL); err != nil {
+			return fmt.Errorf("repository.MigrateUp: %w", err)
+		}
+	}
+
+	// canceled on SIGINT or SIGTERM, which begins the shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	lc := lifecycle.New(
+		lifecycle.WithPreStopDelay(cfg.Server.PreStopDelay),
+		lifecycle.WithGracePeriod(cfg.Server.ShutdownTimeout),
+	)
+
+	// the components created before a failure are stopped, e.g. the pool is closed and the spans are flushed
+	if err := setupComponents(ctx, cfg, lc); err != nil {
+		return lc.Abort(fmt.Errorf("setupComponents: %w", err))
+	}
+
+	if err := lc.Run(ctx); err != nil {
+		return fmt.Errorf("lc.Run: %w", err)
+	}
+
+	slog.Info("server gracefully stopped")
+
+	return nil
+}
+
+// setupComponents creates the components and appends their hooks to lc in the order they are started.
+func setupComponents(ctx context.Context, cfg config.Config, lc *lifecycle.Lifecycle) error {
+	verifier, err := newVerifier(cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("newVerifier: %w", err)
+	}
+
+	poolConfig, err := cfg.Database.PoolConfig()
+	if err != nil {
+		return fmt.Errorf("cfg.Database.PoolConfig: %w", err)
+	}
+
+	tp, shutdownTracing, err := newTracerProvider(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("newTracerProvider: %w", err)
+	}
+
+	// appended first to be stopped last, so that the spans of the other components are flushed
+	lc.Append(lifecycle.Hook{Name: "tracing", OnStop: shutdownTracing})
+
+	poolConfig.ConnConfig.Tracer = repository.NewQueryTracer(tp)
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return fmt.Errorf("pgxpool.NewWithConfig: %w", err)
+	}
+
+	lc.Append(lifecycle.Hook{
+		Name: "database pool",
+		OnStop: func(context.Context) error {
+			pool.Close()
+			return nil
+		},
+	})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		repository.NewPoolCollector(pool),
+	)
+
+	repo, err := repository.New(pool, repositoryOptions(cfg.Database)...)
+	if err != nil {
+		return fmt.Errorf("repository.New: %w", err)
+	}
+
+	cartService, err := service.NewCart(repo, repo, repo, repo)
+	if err != nil {
+		return fmt.Errorf("service.NewCart: %w", err)
+	}
+
+	cartService, err = service.NewCartMetrics(cartService, registry)
+	if err != nil {
+		return fmt.Errorf("service.NewCartMetrics: %w", err)
+	}
+
+	cartService, err = service.NewCartTracing(cartService, tp)
+	if err != nil {
+		return fmt.Errorf("service.NewCartTracing: %w", err)
+	}
+
+	cartHandler, err := rest.NewCart(cartService)
+	if err != nil {
+		return fmt.Errorf("rest.NewCart: %w", err)
+	}
+
+	orderService, err := service.NewOrder(repo)
+	if err != nil {
+		return fmt.Errorf("service.NewOrder: %w", err)
+	}
+
+	orderHandler, err := rest.NewOrder(orderService)
+	if err != nil {
+		return fmt.Errorf("rest.NewOrder: %w", err)
+	}
+
+	idempotencyService, err := service.NewIdempotency(repo, cfg.Idempotency.TTL)
+	if err != nil {
+		return fmt.Errorf("service.NewIdempotency: %w", err)
+	}
+
+	// stopped before the database pool it uses
+	lc.Append(lifecycle.Every("idempotency key purge", cfg.Idempotency.PurgeInterval, func(ctx context.Context) {
+		purged, err := idempotencyService.PurgeExpired(ctx)
+		if err != nil {
+			slog.Error("idempotency key purge failed", "err", err)
+			return
+		}
+
+		slog.Debug("idempotency keys purged", "count", purged)
+	}))
+
+	health := rest.NewHealth(map[string]rest.HealthCheck{
+		"database": pool.Ping,
+		"schema": func(ctx context.Context) error {
+			return repository.CheckSchema(ctx, pool)
+		},
+	})
+
+	// the readiness probe fails during the pre-stop delay, while the in-flight requests complete
+	lc.BeforeStop(health.Drain)
+
+	router := rest.SetupRouter(cartHandler, orderHandler, verifier, rest.WithMetrics(registry),
+		rest.WithTracing(cfg.Tracing.ServiceName, tp), rest.WithHealth(health), rest.WithIdempotency(idempotencyService))
+
+	lc.Append(serverHook(lc, cfg.Server, router))
+
+	return nil
+}
+
+func repositoryOptions(cfg config.Database) []repository.Option {
+	opts := []repository.Option{repository.WithTxRetries(cfg.TxRetries, cfg.TxRetryDelay)}
+
+	if cfg.TxIsolation != "" {
+		opts = append(opts, repository.WithIsolationLevel(pgx.TxIsoLevel(cfg.TxIsolation)))
+	}
+
+	return opts
+}
+
+func newVerifier(cfg config.Auth) (*auth.Verifier, error) {
+	opts := []auth.Option{
+		auth.WithIssuer(cfg.Issuer),
+		auth.WithAudience(cfg.Audience),
+		auth.WithAdminScope(cfg.AdminScope),
+	}
+
+	if cfg.HMACSecret != "" {
+		opts = append(opts, auth.WithHMACSecret([]byte(cfg.HMACSecret)))
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := auth.LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth.LoadJWKS[%s]: %w", cfg.JWKSFile, err)
+		}
+
+		opts = append(opts, auth.WithRSAKeys(keys))
+	}
+
+	verifier, err := auth.NewVerifier(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("auth.NewVerifier: %w", err)
+	}
+
+	return verifier, nil
+}
+
+// serverHook listens on startup, so that an address in use fails the startup, and serves in the background.
+func serverHook(lc *lifecycle.Lifecycle, cfg config.Server, handler http.Handler) lifecycle.Hook {
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	return lifecycle.Hook{
+		Name: "http server",
+		OnStart: func(ctx context.Context) error {
+			var listenConfig net.ListenConfig
+
+			listener, err := listenConfig.Listen(ctx, "tcp", server.Addr)
+			if err != nil {
+				return fmt.Errorf("listenConfig.Listen: %w", err)
+			}
+
+			go func() {
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					lc.Fail(fmt.Errorf("server.Serve: %w", err))
+				}
+			}()
+
+			slog.Info("server started", "addr", listener.Addr().String())
+
+			return nil
+		},
+		// waits for the in-flight requests to complete
+		OnStop: server.Shutdown,
+	}
+}
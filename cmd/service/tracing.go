@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nikolayk812/go-tests/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"os"
+)
+
+// newTracerProvider returns the tracer provider exporting the spans as configured and the shutdown func
+// flushing the pending spans. It also sets the global provider and the W3C trace context propagator.
+func newTracerProvider(ctx context.Context, cfg config.Tracing) (trace.TracerProvider, func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Exporter == "none" {
+		tp := noop.NewTracerProvider()
+		otel.SetTracerProvider(tp)
+
+		return tp, func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeExporter, err := newSpanExporter(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("newSpanExporter[%s]: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("resource.Merge: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	shutdown := func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), closeExporter())
+	}
+
+	return tp, shutdown, nil
+}
+
+// newSpanExporter returns the exporter and the func closing its output once the exporter is shut down.
+func newSpanExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+
+	switch cfg.Exporter {
+	case "stdout":
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, nil, fmt.Errorf("stdouttrace.New: %w", err)
+		}
+
+		return exporter, noClose, nil
+	case "file":
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("os.OpenFile: %w", err)
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			return nil, nil, errors.Join(fmt.Errorf("stdouttrace.New: %w", err), f.Close())
+		}
+
+		return exporter, f.Close, nil
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("otlptracehttp.New: %w", err)
+		}
+
+		return exporter, noClose, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown exporter: %s", cfg.Exporter)
+	}
+}
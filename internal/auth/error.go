@@ -0,0 +1,5 @@
+package auth
+
+import "errors"
+
+var ErrInvalidToken = errors.New("invalid token")
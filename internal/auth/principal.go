@@ -0,0 +1,29 @@
+package auth
+
+import "context"
+
+// Principal is the authenticated caller, Subject is the owner ID of the caller's cart and orders.
+type Principal struct {
+	Subject string
+	Scopes  []string
+
+	// Admin is set if the token has the admin scope, admins access every owner
+	Admin bool
+}
+
+// CanAccess tells whether the principal may access the carts and orders of the owner.
+func (p Principal) CanAccess(ownerID string) bool {
+	return p.Admin || p.Subject == ownerID
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal of an authenticated request.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
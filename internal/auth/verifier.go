@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"slices"
+	"strings"
+	"time"
+)
+
+// leeway tolerates clock skew between the token issuer and the service.
+const leeway = 30 * time.Second
+
+// Verifier verifies HS256 tokens signed with a shared secret and RS256 tokens signed with a key of a JWKS.
+type Verifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+
+	issuer     string
+	audience   string
+	adminScope string
+
+	parser *jwt.Parser
+}
+
+// Option configures the verifier created by NewVerifier.
+type Option func(v *Verifier)
+
+// WithHMACSecret enables HS256 tokens signed with the secret.
+func WithHMACSecret(secret []byte) Option {
+	return func(v *Verifier) {
+		v.hmacSecret = secret
+	}
+}
+
+// WithRSAKeys enables RS256 tokens signed with one of the keys, the map key is the JWK key ID.
+func WithRSAKeys(keys map[string]*rsa.PublicKey) Option {
+	return func(v *Verifier) {
+		v.rsaKeys = keys
+	}
+}
+
+// WithIssuer requires the iss claim to be the issuer.
+func WithIssuer(issuer string) Option {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience requires the aud claim to contain the audience.
+func WithAudience(audience string) Option {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+// WithAdminScope sets the scope which grants access to the carts and orders of every owner.
+func WithAdminScope(scope string) Option {
+	return func(v *Verifier) {
+		v.adminScope = scope
+	}
+}
+
+// NewVerifier returns a verifier of the tokens signed with the configured keys, at least one key is required.
+func NewVerifier(opts ...Option) (*Verifier, error) {
+	v := &Verifier{}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	// the methods are limited to the configured keys, so that a token cannot pick a weaker one
+	var methods []string
+	if len(v.hmacSecret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(v.rsaKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("neither hmac secret nor rsa keys are set")
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if v.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(v.audience))
+	}
+
+	v.parser = jwt.NewParser(parserOpts...)
+
+	return v, nil
+}
+
+// claims are the registered claims and the OAuth 2.0 scope claim, a space separated list of scopes.
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope,omitempty"`
+}
+
+// Verify returns the principal of a valid token, otherwise an error wrapping ErrInvalidToken.
+func (v *Verifier) Verify(token string) (Principal, error) {
+	var c claims
+
+	if _, err := v.parser.ParseWithClaims(token, &c, v.key); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if c.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: subject is empty", ErrInvalidToken)
+	}
+
+	scopes := strings.Fields(c.Scope)
+
+	return Principal{
+		Subject: c.Subject,
+		Scopes:  scopes,
+		Admin:   v.adminScope != "" && slices.Contains(scopes, v.adminScope),
+	}, nil
+}
+
+// key returns the verification key for the token signing method and key ID.
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+
+		// a token without the key ID is accepted if there is a single key
+		if kid == "" && len(v.rsaKeys) == 1 {
+			for _, key := range v.rsaKeys {
+				return key, nil
+			}
+		}
+
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
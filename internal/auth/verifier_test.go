@@ -0,0 +1,264 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nikolayk812/go-tests/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	verifier, err := auth.NewVerifier(
+		auth.WithHMACSecret([]byte(testSecret)),
+		auth.WithRSAKeys(map[string]*rsa.PublicKey{"key1": &rsaKey.PublicKey}),
+		auth.WithIssuer("issuer1"),
+		auth.WithAdminScope("admin"),
+	)
+	require.NoError(t, err)
+
+	rsaOnly, err := auth.NewVerifier(auth.WithRSAKeys(map[string]*rsa.PublicKey{"key1": &rsaKey.PublicKey}))
+	require.NoError(t, err)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"sub": "owner1", "iss": "issuer1", "exp": time.Now().Add(time.Hour).Unix()}
+	}
+
+	with := func(key string, value any) jwt.MapClaims {
+		claims := valid()
+		claims[key] = value
+		return claims
+	}
+
+	without := func(key string) jwt.MapClaims {
+		claims := valid()
+		delete(claims, key)
+		return claims
+	}
+
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustMarshalPKIX(t, &rsaKey.PublicKey)})
+
+	tests := []struct {
+		name     string
+		verifier *auth.Verifier
+		token    string
+		want     auth.Principal
+		wantErr  bool
+	}{
+		{
+			name:  "HS256",
+			token: signHS256(t, testSecret, valid()),
+			want:  auth.Principal{Subject: "owner1", Scopes: []string{}},
+		},
+		{
+			name:  "RS256",
+			token: signRS256(t, rsaKey, "key1", valid()),
+			want:  auth.Principal{Subject: "owner1", Scopes: []string{}},
+		},
+		{
+			name:     "RS256 without key ID and a single key",
+			verifier: rsaOnly,
+			token:    signRS256(t, rsaKey, "", valid()),
+			want:     auth.Principal{Subject: "owner1", Scopes: []string{}},
+		},
+		{
+			name:  "admin scope",
+			token: signHS256(t, testSecret, with("scope", "orders:read admin")),
+			want:  auth.Principal{Subject: "owner1", Scopes: []string{"orders:read", "admin"}, Admin: true},
+		},
+		{
+			name:    "wrong secret",
+			token:   signHS256(t, "another-secret-another-secret-00", valid()),
+			wantErr: true,
+		},
+		{
+			name:    "unknown key ID",
+			token:   signRS256(t, rsaKey, "key2", valid()),
+			wantErr: true,
+		},
+		{
+			name:    "wrong RSA key",
+			token:   signRS256(t, otherRSAKey, "key1", valid()),
+			wantErr: true,
+		},
+		{
+			name:     "HS256 signed with the RSA public key",
+			verifier: rsaOnly,
+			token:    signHS256(t, string(publicKeyPEM), valid()),
+			wantErr:  true,
+		},
+		{
+			name:    "unsigned",
+			token:   signNone(t, valid()),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   signHS256(t, testSecret, with("exp", time.Now().Add(-time.Hour).Unix())),
+			wantErr: true,
+		},
+		{
+			name:    "expiration missing",
+			token:   signHS256(t, testSecret, without("exp")),
+			wantErr: true,
+		},
+		{
+			name:    "wrong issuer",
+			token:   signHS256(t, testSecret, with("iss", "issuer2")),
+			wantErr: true,
+		},
+		{
+			name:    "subject missing",
+			token:   signHS256(t, testSecret, without("sub")),
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			token:   "not-a-token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := verifier
+			if tt.verifier != nil {
+				v = tt.verifier
+			}
+
+			principal, err := v.Verify(tt.token)
+			if tt.wantErr {
+				require.ErrorIs(t, err, auth.ErrInvalidToken)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, principal)
+		})
+	}
+}
+
+func TestNewVerifier_NoKeys(t *testing.T) {
+	_, err := auth.NewVerifier(auth.WithIssuer("issuer1"))
+	require.Error(t, err)
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rsaJWK := map[string]string{
+		"kty": "RSA",
+		"kid": "key1",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+	}
+
+	encryptionJWK := map[string]string{"kty": "RSA", "kid": "key2", "use": "enc", "n": rsaJWK["n"], "e": rsaJWK["e"]}
+	ecJWK := map[string]string{"kty": "EC", "kid": "key3", "crv": "P-256", "x": "AA", "y": "AA"}
+
+	tests := []struct {
+		name    string
+		keys    []map[string]string
+		wantErr bool
+	}{
+		{
+			name: "other keys skipped",
+			keys: []map[string]string{ecJWK, rsaJWK, encryptionJWK},
+		},
+		{
+			name:    "no RSA signature keys",
+			keys:    []map[string]string{ecJWK},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key ID",
+			keys:    []map[string]string{rsaJWK, rsaJWK},
+			wantErr: true,
+		},
+		{
+			name:    "invalid modulus",
+			keys:    []map[string]string{{"kty": "RSA", "kid": "key1", "n": "!", "e": rsaJWK["e"]}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(map[string]any{"keys": tt.keys})
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			require.NoError(t, os.WriteFile(path, data, 0o600))
+
+			keys, err := auth.LoadJWKS(path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]*rsa.PublicKey{"key1": &rsaKey.PublicKey}, keys)
+		})
+	}
+}
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return token
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func signNone(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	return token
+}
+
+func mustMarshalPKIX(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return der
+}
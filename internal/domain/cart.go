@@ -2,17 +2,83 @@ package domain
 
 import (
 	"github.com/google/uuid"
+	"sort"
 	"time"
 )
 
 type Cart struct {
 	OwnerID string
 	Items   []CartItem
+
+	// Version changes with every change of the items, it is 0 for a cart which never had items.
+	Version int64
+}
+
+// HasPriceChanges reports whether any item price differs from the catalog price.
+func (c Cart) HasPriceChanges() bool {
+	for _, item := range c.Items {
+		if item.PriceChange != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Totals returns the subtotal of the cart per currency, rounded to the currency minor units
+// and sorted by currency code. Empty cart has no totals.
+func (c Cart) Totals() []Money {
+	subtotals := make(map[string]Money)
+
+	for _, item := range c.Items {
+		code := item.Price.Currency.String()
+
+		subtotal, ok := subtotals[code]
+		if !ok {
+			subtotal = Zero(item.Price.Currency)
+		}
+
+		// currencies always match as subtotals are keyed by currency
+		subtotal, _ = subtotal.Add(item.Subtotal())
+		subtotals[code] = subtotal
+	}
+
+	totals := make([]Money, 0, len(subtotals))
+	for _, subtotal := range subtotals {
+		totals = append(totals, subtotal.Round())
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Currency.String() < totals[j].Currency.String()
+	})
+
+	return totals
 }
 
 type CartItem struct {
 	ProductID uuid.UUID
 	Price     Money
+	Quantity  int
 
 	CreatedAt time.Time
+
+	// PriceChange is set if the catalog price differs from the item price, it is not persisted.
+	PriceChange *PriceChange
+}
+
+type PriceChange struct {
+	OldPrice  Money
+	NewPrice  Money
+	ChangedAt time.Time
+}
+
+// ItemPrice is a product price accepted by the cart owner.
+type ItemPrice struct {
+	ProductID uuid.UUID
+	Price     Money
+}
+
+// Subtotal returns the item price multiplied by the quantity.
+func (i CartItem) Subtotal() Money {
+	return i.Price.Mul(i.Quantity)
 }
@@ -0,0 +1,52 @@
+package domain_test
+
+import (
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/currency"
+	"testing"
+)
+
+func TestCart_Totals(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []domain.CartItem
+		want  []domain.Money
+	}{
+		{
+			name: "empty cart",
+			want: []domain.Money{},
+		},
+		{
+			name: "single currency",
+			items: []domain.CartItem{
+				{ProductID: uuid.New(), Price: eur("10.25"), Quantity: 2},
+				{ProductID: uuid.New(), Price: eur("0.50"), Quantity: 1},
+			},
+			want: []domain.Money{eur("21")},
+		},
+		{
+			name: "multiple currencies sorted by code",
+			items: []domain.CartItem{
+				{ProductID: uuid.New(), Price: money("3.333", currency.USD), Quantity: 3},
+				{ProductID: uuid.New(), Price: eur("1.10"), Quantity: 1},
+				{ProductID: uuid.New(), Price: money("1.20", currency.USD), Quantity: 1},
+			},
+			want: []domain.Money{eur("1.10"), money("11.20", currency.USD)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := domain.Cart{Items: tt.items}
+
+			totals := cart.Totals()
+
+			assert.Len(t, totals, len(tt.want))
+			for i := range tt.want {
+				assert.True(t, tt.want[i].Equal(totals[i]), "want %s, got %s", tt.want[i], totals[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+	ErrCurrencyMismatch        = errors.New("currency mismatch")
+)
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+	"time"
+)
+
+// ExchangeRate converts money from one currency to another: 1 From = Rate To.
+type ExchangeRate struct {
+	From      currency.Unit
+	To        currency.Unit
+	Rate      decimal.Decimal
+	UpdatedAt time.Time
+}
+
+// Convert returns the money in the target currency, the amount is not rounded.
+func (r ExchangeRate) Convert(m Money) (Money, error) {
+	if m.Currency != r.From {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, r.From)
+	}
+
+	return NewMoney(m.Amount.Mul(r.Rate), r.To), nil
+}
+
+// Invert returns the rate for the opposite direction.
+func (r ExchangeRate) Invert() ExchangeRate {
+	return ExchangeRate{
+		From:      r.To,
+		To:        r.From,
+		Rate:      decimal.NewFromInt(1).Div(r.Rate),
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+// ConvertedTotal is the cart total in a single display currency with the rates used for conversion.
+type ConvertedTotal struct {
+	Total Money
+	Rates []ExchangeRate
+}
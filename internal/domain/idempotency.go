@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// IdempotencyKey is the Idempotency-Key header of a request, it makes the retries of the request safe.
+// Scope is the caller which sent the key, so that the keys of different callers never collide.
+type IdempotencyKey struct {
+	Scope string
+	Key   string
+
+	// Fingerprint identifies the request, every retry with the key must send the same request
+	Fingerprint string
+
+	// Response is nil while the request is in progress
+	Response *IdempotentResponse
+
+	CreatedAt time.Time
+}
+
+// IdempotentResponse is the response of a request with an idempotency key, it is replayed on the retries.
+type IdempotentResponse struct {
+	StatusCode int
+	Header     map[string]string
+	Body       []byte
+}
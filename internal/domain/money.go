@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"github.com/shopspring/decimal"
 	"golang.org/x/text/currency"
 )
@@ -9,3 +10,90 @@ type Money struct {
 	Amount   decimal.Decimal
 	Currency currency.Unit
 }
+
+func NewMoney(amount decimal.Decimal, unit currency.Unit) Money {
+	return Money{
+		Amount:   amount,
+		Currency: unit,
+	}
+}
+
+// Zero returns zero amount in the given currency.
+func Zero(unit currency.Unit) Money {
+	return NewMoney(decimal.Zero, unit)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(m.Amount.Add(other.Amount), m.Currency), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(m.Amount.Sub(other.Amount), m.Currency), nil
+}
+
+// Mul multiplies the amount by the quantity, e.g. unit price by number of items.
+func (m Money) Mul(quantity int) Money {
+	return NewMoney(m.Amount.Mul(decimal.NewFromInt(int64(quantity))), m.Currency)
+}
+
+// Cmp returns -1, 0 or +1 if m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+// Equal reports whether amounts and currencies are the same, amounts are compared regardless of their scale.
+func (m Money) Equal(other Money) bool {
+	return m.Currency == other.Currency && m.Amount.Equal(other.Amount)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount.IsNegative()
+}
+
+// Scale returns the number of minor unit digits of the currency, e.g. 2 for EUR and 0 for JPY.
+func (m Money) Scale() int {
+	scale, _ := currency.Standard.Rounding(m.Currency)
+	return scale
+}
+
+// Round rounds the amount half away from zero to the minor units of the currency.
+func (m Money) Round() Money {
+	scale, increment := currency.Standard.Rounding(m.Currency)
+
+	if increment <= 1 {
+		return NewMoney(m.Amount.Round(int32(scale)), m.Currency)
+	}
+
+	// rounds to a multiple of the increment, e.g. 0.05 for scale 2 and increment 5
+	step := decimal.New(int64(increment), -int32(scale))
+
+	return NewMoney(m.Amount.Div(step).Round(0).Mul(step), m.Currency)
+}
+
+func (m Money) String() string {
+	return m.Amount.StringFixed(int32(m.Scale())) + " " + m.Currency.String()
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	return nil
+}
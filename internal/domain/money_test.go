@@ -0,0 +1,87 @@
+package domain_test
+
+import (
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/currency"
+	"testing"
+)
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := eur("10.50")
+	b := eur("2.25")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.True(t, eur("12.75").Equal(sum))
+
+	diff, err := a.Sub(b)
+	require.NoError(t, err)
+	assert.True(t, eur("8.25").Equal(diff))
+
+	assert.True(t, eur("31.50").Equal(a.Mul(3)))
+	assert.True(t, eur("0").Equal(a.Mul(0)))
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	cmp, err = b.Cmp(a)
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	assert.True(t, eur("10.5").Equal(eur("10.500")))
+	assert.False(t, eur("10.5").Equal(money("10.5", currency.USD)))
+}
+
+func TestMoney_CurrencyMismatch(t *testing.T) {
+	a := eur("1")
+	b := money("1", currency.USD)
+
+	_, err := a.Add(b)
+	require.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+	_, err = a.Sub(b)
+	require.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+	_, err = a.Cmp(b)
+	require.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+}
+
+func TestMoney_Round(t *testing.T) {
+	tests := []struct {
+		name  string
+		money domain.Money
+		want  string
+	}{
+		{name: "EUR rounds half up", money: eur("1.005"), want: "1.01"},
+		{name: "EUR rounds down", money: eur("1.004"), want: "1"},
+		{name: "EUR negative", money: eur("-1.005"), want: "-1.01"},
+		{name: "JPY has no minor units", money: money("100.5", currency.JPY), want: "101"},
+		{name: "BHD has three minor units", money: money("1.2345", currency.MustParseISO("BHD")), want: "1.235"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounded := tt.money.Round()
+
+			assert.Equal(t, tt.want, rounded.Amount.String())
+			assert.Equal(t, tt.money.Currency, rounded.Currency)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "10.50 EUR", eur("10.5").String())
+	assert.Equal(t, "100 JPY", money("100", currency.JPY).String())
+}
+
+func eur(amount string) domain.Money {
+	return money(amount, currency.EUR)
+}
+
+func money(amount string, unit currency.Unit) domain.Money {
+	return domain.NewMoney(decimal.RequireFromString(amount), unit)
+}
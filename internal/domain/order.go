@@ -1,18 +1,46 @@
 package domain
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"time"
 )
 
 type Order struct {
+	ID      uuid.UUID
 	OwnerID string
+	Status  OrderStatus
 	Items   []OrderItem
+
+	CreatedAt time.Time
+}
+
+// TransitionTo moves the order to the next status if the transition is allowed.
+func (o *Order) TransitionTo(next OrderStatus) error {
+	if !o.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, o.Status, next)
+	}
+
+	o.Status = next
+
+	return nil
 }
 
 type OrderItem struct {
 	ProductID uuid.UUID
 	Price     Money
+	Quantity  int
 
 	CreatedAt time.Time
 }
+
+// OrderCursor points to the last order of a page, orders are listed newest first.
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type OrderPage struct {
+	Orders []Order
+	Next   *OrderCursor // nil on the last page
+}
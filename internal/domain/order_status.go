@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// orderStatusTransitions lists allowed next statuses, delivered and cancelled are final.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:    {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped: {OrderStatusDelivered},
+}
+
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	switch status := OrderStatus(s); status {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown order status[%s]", s)
+	}
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OrderStatusChange is an entry of the order status history,
+// From is empty for the entry recorded when the order is created.
+type OrderStatusChange struct {
+	From      OrderStatus
+	To        OrderStatus
+	ChangedBy string
+	ChangedAt time.Time
+}
@@ -0,0 +1,55 @@
+package domain_test
+
+import (
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestOrder_TransitionTo(t *testing.T) {
+	tests := []struct {
+		from    domain.OrderStatus
+		to      domain.OrderStatus
+		allowed bool
+	}{
+		{from: domain.OrderStatusPending, to: domain.OrderStatusPaid, allowed: true},
+		{from: domain.OrderStatusPending, to: domain.OrderStatusCancelled, allowed: true},
+		{from: domain.OrderStatusPaid, to: domain.OrderStatusShipped, allowed: true},
+		{from: domain.OrderStatusPaid, to: domain.OrderStatusCancelled, allowed: true},
+		{from: domain.OrderStatusShipped, to: domain.OrderStatusDelivered, allowed: true},
+
+		{from: domain.OrderStatusPending, to: domain.OrderStatusPending},
+		{from: domain.OrderStatusPending, to: domain.OrderStatusShipped},
+		{from: domain.OrderStatusPending, to: domain.OrderStatusDelivered},
+		{from: domain.OrderStatusPaid, to: domain.OrderStatusPending},
+		{from: domain.OrderStatusShipped, to: domain.OrderStatusCancelled},
+		{from: domain.OrderStatusDelivered, to: domain.OrderStatusCancelled},
+		{from: domain.OrderStatusCancelled, to: domain.OrderStatusPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			order := domain.Order{Status: tt.from}
+
+			err := order.TransitionTo(tt.to)
+			if !tt.allowed {
+				require.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+				assert.Equal(t, tt.from, order.Status)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.to, order.Status)
+		})
+	}
+}
+
+func TestParseOrderStatus(t *testing.T) {
+	status, err := domain.ParseOrderStatus("shipped")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusShipped, status)
+
+	_, err = domain.ParseOrderStatus("lost")
+	require.Error(t, err)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Product struct {
+	ID    uuid.UUID
+	Name  string
+	Price Money
+
+	UpdatedAt time.Time
+}
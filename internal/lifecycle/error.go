@@ -0,0 +1,6 @@
+package lifecycle
+
+import "errors"
+
+// ErrStopTimeout is returned if a component does not stop within the grace period.
+var ErrStopTimeout = errors.New("component did not stop within the grace period")
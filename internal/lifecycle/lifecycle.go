@@ -0,0 +1,208 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultGracePeriod = 5 * time.Second
+
+	// expiredStopTimeout is given to the components stopped once the grace period is over,
+	// e.g. the database pool after a blocked HTTP server, to stop on the expired context
+	expiredStopTimeout = 100 * time.Millisecond
+)
+
+// Hook starts and stops a component of the application, e.g. the HTTP server or the database pool.
+// Both funcs are optional.
+type Hook struct {
+	Name string
+
+	// OnStart returns once the component is started, long-running work continues in a goroutine
+	// which reports its failure with Lifecycle.Fail
+	OnStart func(ctx context.Context) error
+
+	// OnStop stops the component before the deadline of ctx
+	OnStop func(ctx context.Context) error
+}
+
+// Lifecycle starts the components in the order they are appended and stops them in the reverse order,
+// so that a component is stopped before the components it depends on.
+type Lifecycle struct {
+	hooks      []Hook
+	beforeStop []func()
+
+	preStopDelay time.Duration
+	gracePeriod  time.Duration
+
+	failed chan error
+}
+
+// Option configures the lifecycle created by New.
+type Option func(l *Lifecycle)
+
+// WithPreStopDelay delays stopping the components once shutdown begins, so that load balancers
+// notice the failing readiness probe and stop routing new requests to the instance.
+func WithPreStopDelay(delay time.Duration) Option {
+	return func(l *Lifecycle) {
+		l.preStopDelay = delay
+	}
+}
+
+// WithGracePeriod bounds the time all the components take to stop, 5 seconds by default.
+func WithGracePeriod(period time.Duration) Option {
+	return func(l *Lifecycle) {
+		l.gracePeriod = period
+	}
+}
+
+func New(opts ...Option) *Lifecycle {
+	l := &Lifecycle{
+		gracePeriod: defaultGracePeriod,
+		failed:      make(chan error, 1),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Append registers the hook of a component, it must be called before Run.
+func (l *Lifecycle) Append(hook Hook) {
+	l.hooks = append(l.hooks, hook)
+}
+
+// BeforeStop registers fn to run once shutdown begins, before the pre-stop delay,
+// e.g. to fail the readiness probe. It must be called before Run.
+func (l *Lifecycle) BeforeStop(fn func()) {
+	l.beforeStop = append(l.beforeStop, fn)
+}
+
+// Fail shuts the application down with err, e.g. if the HTTP server stops serving.
+// Only the first failure is reported by Run.
+func (l *Lifecycle) Fail(err error) {
+	select {
+	case l.failed <- err:
+	default:
+	}
+}
+
+// Run starts the components, waits until ctx is done or a component fails and stops the started components.
+// A failed start stops the components started before it.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	started, err := l.start(ctx)
+	if err != nil {
+		return errors.Join(err, l.stop(started))
+	}
+
+	select {
+	case <-ctx.Done():
+		slog.Info("shutdown started")
+	case err = <-l.failed:
+		slog.Error("shutdown started on failure", "err", err)
+	}
+
+	l.drain()
+
+	return errors.Join(err, l.stop(started))
+}
+
+// Abort stops the components appended so far if the setup fails before Run, none of them is started then.
+// It returns err joined with the stop errors.
+func (l *Lifecycle) Abort(err error) error {
+	return errors.Join(err, l.stop(l.hooks))
+}
+
+// start returns the hooks of the started components, including the failed one,
+// as it may have started partially.
+func (l *Lifecycle) start(ctx context.Context) ([]Hook, error) {
+	for i, hook := range l.hooks {
+		if hook.OnStart == nil {
+			continue
+		}
+
+		start := time.Now()
+
+		if err := hook.OnStart(ctx); err != nil {
+			return l.hooks[:i+1], fmt.Errorf("start %s: %w", hook.Name, err)
+		}
+
+		slog.Debug("component started", "component", hook.Name, "duration", time.Since(start))
+	}
+
+	return l.hooks, nil
+}
+
+func (l *Lifecycle) drain() {
+	for _, fn := range l.beforeStop {
+		fn()
+	}
+
+	if l.preStopDelay > 0 {
+		slog.Info("waiting before stopping the components", "delay", l.preStopDelay)
+		time.Sleep(l.preStopDelay)
+	}
+}
+
+// stop stops the components in the reverse order within the grace period, a component which does not stop
+// in time is reported and left behind, so that the remaining components still get stopped.
+func (l *Lifecycle) stop(hooks []Hook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.gracePeriod)
+	defer cancel()
+
+	var errs []error
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		start := time.Now()
+
+		if err := stopHook(ctx, hook); err != nil {
+			slog.Error("component stop failed", "component", hook.Name, "err", err)
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+
+			continue
+		}
+
+		slog.Debug("component stopped", "component", hook.Name, "duration", time.Since(start))
+	}
+
+	return errors.Join(errs...)
+}
+
+func stopHook(ctx context.Context, hook Hook) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.OnStop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return stopErr(ctx, err)
+	case <-ctx.Done():
+	}
+
+	select {
+	case err := <-done:
+		return stopErr(ctx, err)
+	case <-time.After(expiredStopTimeout):
+		return ErrStopTimeout
+	}
+}
+
+func stopErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		// e.g. http.Server.Shutdown returns the context error once the grace period is over
+		return fmt.Errorf("%w: %w", ErrStopTimeout, err)
+	}
+
+	return err
+}
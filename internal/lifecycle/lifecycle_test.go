@@ -0,0 +1,205 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"github.com/nikolayk812/go-tests/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_Run(t *testing.T) {
+	errStart := errors.New("address already in use")
+	errServe := errors.New("connection reset")
+
+	tests := []struct {
+		name       string
+		failStart  string
+		failServe  string
+		blockStop  string
+		wantEvents []string
+		wantErr    []error
+	}{
+		{
+			name: "stopped in reverse order",
+			wantEvents: []string{
+				"start a", "start b", "start c",
+				"before stop",
+				"stop c", "stop b", "stop a",
+			},
+		},
+		{
+			name:      "failed start stops the started components",
+			failStart: "b",
+			wantEvents: []string{
+				"start a", "start b",
+				"stop b", "stop a",
+			},
+			wantErr: []error{errStart},
+		},
+		{
+			name:      "failed component shuts down",
+			failServe: "c",
+			wantEvents: []string{
+				"start a", "start b", "start c",
+				"before stop",
+				"stop c", "stop b", "stop a",
+			},
+			wantErr: []error{errServe},
+		},
+		{
+			name:      "blocked stop is reported",
+			blockStop: "b",
+			wantEvents: []string{
+				"start a", "start b", "start c",
+				"before stop",
+				"stop c", "stop b", "stop a",
+			},
+			wantErr: []error{lifecycle.ErrStopTimeout},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu     sync.Mutex
+				events []string
+			)
+
+			record := func(event string) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				events = append(events, event)
+			}
+
+			unblock := make(chan struct{})
+			defer close(unblock)
+
+			lc := lifecycle.New(lifecycle.WithGracePeriod(50 * time.Millisecond))
+			lc.BeforeStop(func() { record("before stop") })
+
+			for _, name := range []string{"a", "b", "c"} {
+				lc.Append(lifecycle.Hook{
+					Name: name,
+					OnStart: func(ctx context.Context) error {
+						record("start " + name)
+
+						if name == tt.failStart {
+							return errStart
+						}
+
+						if name == tt.failServe {
+							go lc.Fail(errServe)
+						}
+
+						return nil
+					},
+					OnStop: func(ctx context.Context) error {
+						record("stop " + name)
+
+						if name == tt.blockStop {
+							// ignores the deadline of ctx
+							<-unblock
+						}
+
+						return nil
+					},
+				})
+			}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			if tt.failStart == "" && tt.failServe == "" {
+				cancel()
+			}
+			defer cancel()
+
+			err := lc.Run(ctx)
+
+			for _, wantErr := range tt.wantErr {
+				assert.ErrorIs(t, err, wantErr)
+			}
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			assert.Equal(t, tt.wantEvents, events)
+		})
+	}
+}
+
+func TestLifecycle_PreStopDelay(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	var drainedAt, stoppedAt time.Time
+
+	lc := lifecycle.New(lifecycle.WithPreStopDelay(delay))
+	lc.BeforeStop(func() { drainedAt = time.Now() })
+	lc.Append(lifecycle.Hook{
+		Name: "server",
+		OnStop: func(ctx context.Context) error {
+			stoppedAt = time.Now()
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.NoError(t, lc.Run(ctx))
+
+	// the components keep running while the load balancers notice the failing readiness probe
+	assert.GreaterOrEqual(t, stoppedAt.Sub(drainedAt), delay)
+}
+
+func TestLifecycle_StopError(t *testing.T) {
+	errFlush := errors.New("exporter unavailable")
+
+	lc := lifecycle.New()
+	lc.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: func(ctx context.Context) error { return errFlush },
+	})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := lc.Run(ctx)
+	require.ErrorIs(t, err, errFlush)
+	assert.ErrorContains(t, err, "stop tracing")
+}
+
+func TestLifecycle_Abort(t *testing.T) {
+	errSetup := errors.New("invalid jwks file")
+
+	var events []string
+
+	lc := lifecycle.New()
+	lc.BeforeStop(func() { events = append(events, "before stop") })
+
+	for _, name := range []string{"tracing", "database pool"} {
+		lc.Append(lifecycle.Hook{
+			Name: name,
+			OnStart: func(ctx context.Context) error {
+				events = append(events, "start "+name)
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				events = append(events, "stop "+name)
+				return nil
+			},
+		})
+	}
+
+	err := lc.Abort(errSetup)
+	require.ErrorIs(t, err, errSetup)
+
+	// nothing is started or drained, the components created so far are stopped in the reverse order
+	assert.Equal(t, []string{"stop database pool", "stop tracing"}, events)
+}
@@ -0,0 +1,59 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// Every returns the hook of a background worker which runs fn once started and then every interval.
+// On stop the context of fn is canceled and the run in progress is awaited, a worker which never started
+// stops right away.
+func Every(name string, interval time.Duration, fn func(ctx context.Context)) Hook {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+
+	return Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			// the start context is canceled once shutdown begins, while the worker runs until it is stopped
+			ctx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+			done = make(chan struct{})
+
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					fn(ctx)
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			// not started, e.g. stopped by Abort
+			if cancel == nil {
+				return nil
+			}
+
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
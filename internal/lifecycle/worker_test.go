@@ -0,0 +1,64 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"github.com/nikolayk812/go-tests/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	var runs atomic.Int32
+
+	hook := lifecycle.Every("worker", 10*time.Millisecond, func(ctx context.Context) {
+		runs.Add(1)
+	})
+
+	require.NoError(t, hook.OnStart(t.Context()))
+
+	assert.Eventually(t, func() bool {
+		return runs.Load() >= 3
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, hook.OnStop(t.Context()))
+
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load(), "no runs after stop")
+}
+
+func TestEvery_StopAwaitsRun(t *testing.T) {
+	started := make(chan struct{})
+
+	hook := lifecycle.Every("worker", time.Hour, func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		// the run in progress outlives the grace period
+		time.Sleep(time.Second)
+	})
+
+	require.NoError(t, hook.OnStart(t.Context()))
+	<-started
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, hook.OnStop(ctx), context.DeadlineExceeded)
+}
+
+func TestEvery_AbortBeforeStart(t *testing.T) {
+	errSetup := errors.New("invalid jwks file")
+
+	lc := lifecycle.New()
+	lc.Append(lifecycle.Every("worker", time.Hour, func(ctx context.Context) {
+		t.Error("unexpected run")
+	}))
+
+	err := lc.Abort(errSetup)
+	require.ErrorIs(t, err, errSetup)
+	assert.NotErrorIs(t, err, lifecycle.ErrStopTimeout)
+}
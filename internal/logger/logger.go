@@ -0,0 +1,24 @@
+// Package logger carries a request-scoped slog logger through context.Context,
+// so that the logs of all layers handling a request share its attributes, e.g. the request ID.
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+// With returns a copy of ctx carrying the logger.
+func With(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// From returns the logger carried by ctx, or the default logger if there is none.
+func From(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
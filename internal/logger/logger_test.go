@@ -0,0 +1,23 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/nikolayk812/go-tests/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestFrom(t *testing.T) {
+	assert.Same(t, slog.Default(), logger.From(context.Background()))
+
+	var buf bytes.Buffer
+	requestLogger := slog.New(slog.NewTextHandler(&buf, nil)).With("request_id", "request-1")
+
+	ctx := logger.With(context.Background(), requestLogger)
+	logger.From(ctx).Info("order created")
+
+	assert.Same(t, requestLogger, logger.From(ctx))
+	assert.Contains(t, buf.String(), "request_id=request-1")
+}
@@ -9,6 +9,14 @@ import (
 //go:generate mockery --name=CartRepository --structname=MockCartRepository --output=. --outpkg=port --filename=cart_repository_mock.go
 type CartRepository interface {
 	GetCart(ctx context.Context, ownerID string) (domain.Cart, error)
+	// AddItem increments the quantity if the product is already in the cart with the same price.
 	AddItem(ctx context.Context, ownerID string, item domain.CartItem) error
 	DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) (bool, error)
+	// DeleteItems returns the number of deleted items, product IDs not in the cart are ignored.
+	DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error)
+	ClearCart(ctx context.Context, ownerID string) error
+	UpdateItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) (bool, error)
+	UpdateItemPrice(ctx context.Context, ownerID string, productID uuid.UUID, price domain.Money) (bool, error)
+	// LockCart returns the cart version, within a transaction the cart can not change until the transaction ends.
+	LockCart(ctx context.Context, ownerID string) (int64, error)
 }
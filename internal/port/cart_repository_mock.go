@@ -34,6 +34,24 @@ func (_m *MockCartRepository) AddItem(ctx context.Context, ownerID string, item
 	return r0
 }
 
+// ClearCart provides a mock function with given fields: ctx, ownerID
+func (_m *MockCartRepository) ClearCart(ctx context.Context, ownerID string) error {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearCart")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteItem provides a mock function with given fields: ctx, ownerID, productID
 func (_m *MockCartRepository) DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) (bool, error) {
 	ret := _m.Called(ctx, ownerID, productID)
@@ -62,6 +80,34 @@ func (_m *MockCartRepository) DeleteItem(ctx context.Context, ownerID string, pr
 	return r0, r1
 }
 
+// DeleteItems provides a mock function with given fields: ctx, ownerID, productIDs
+func (_m *MockCartRepository) DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	ret := _m.Called(ctx, ownerID, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItems")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID) (int, error)); ok {
+		return rf(ctx, ownerID, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID) int); ok {
+		r0 = rf(ctx, ownerID, productIDs)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []uuid.UUID) error); ok {
+		r1 = rf(ctx, ownerID, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCart provides a mock function with given fields: ctx, ownerID
 func (_m *MockCartRepository) GetCart(ctx context.Context, ownerID string) (domain.Cart, error) {
 	ret := _m.Called(ctx, ownerID)
@@ -90,6 +136,90 @@ func (_m *MockCartRepository) GetCart(ctx context.Context, ownerID string) (doma
 	return r0, r1
 }
 
+// LockCart provides a mock function with given fields: ctx, ownerID
+func (_m *MockCartRepository) LockCart(ctx context.Context, ownerID string) (int64, error) {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockCart")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItemPrice provides a mock function with given fields: ctx, ownerID, productID, price
+func (_m *MockCartRepository) UpdateItemPrice(ctx context.Context, ownerID string, productID uuid.UUID, price domain.Money) (bool, error) {
+	ret := _m.Called(ctx, ownerID, productID, price)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItemPrice")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, domain.Money) (bool, error)); ok {
+		return rf(ctx, ownerID, productID, price)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, domain.Money) bool); ok {
+		r0 = rf(ctx, ownerID, productID, price)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, domain.Money) error); ok {
+		r1 = rf(ctx, ownerID, productID, price)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItemQuantity provides a mock function with given fields: ctx, ownerID, productID, quantity
+func (_m *MockCartRepository) UpdateItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) (bool, error) {
+	ret := _m.Called(ctx, ownerID, productID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItemQuantity")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, int) (bool, error)); ok {
+		return rf(ctx, ownerID, productID, quantity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, int) bool); ok {
+		r0 = rf(ctx, ownerID, productID, quantity)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, ownerID, productID, quantity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMockCartRepository creates a new instance of MockCartRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockCartRepository(t interface {
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"golang.org/x/text/currency"
+)
+
+//go:generate mockery --name=ExchangeRateProvider --structname=MockExchangeRateProvider --output=. --outpkg=port --filename=exchange_rate_provider_mock.go
+type ExchangeRateProvider interface {
+	GetRate(ctx context.Context, from, to currency.Unit) (domain.ExchangeRate, error)
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package port
+
+import (
+	context "context"
+
+	domain "github.com/nikolayk812/go-tests/internal/domain"
+	currency "golang.org/x/text/currency"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockExchangeRateProvider is an autogenerated mock type for the ExchangeRateProvider type
+type MockExchangeRateProvider struct {
+	mock.Mock
+}
+
+// GetRate provides a mock function with given fields: ctx, from, to
+func (_m *MockExchangeRateProvider) GetRate(ctx context.Context, from currency.Unit, to currency.Unit) (domain.ExchangeRate, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRate")
+	}
+
+	var r0 domain.ExchangeRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, currency.Unit, currency.Unit) (domain.ExchangeRate, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, currency.Unit, currency.Unit) domain.ExchangeRate); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		r0 = ret.Get(0).(domain.ExchangeRate)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, currency.Unit, currency.Unit) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockExchangeRateProvider creates a new instance of MockExchangeRateProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockExchangeRateProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockExchangeRateProvider {
+	mock := &MockExchangeRateProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
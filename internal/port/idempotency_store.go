@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"time"
+)
+
+//go:generate mockery --name=IdempotencyStore --structname=MockIdempotencyStore --output=. --outpkg=port --filename=idempotency_store_mock.go
+type IdempotencyStore interface {
+	// ReserveKey stores the key without a response and returns it with true, if the key is already stored
+	// it returns the stored key with false. A key stored longer than ttl ago is replaced as if it was not stored.
+	ReserveKey(ctx context.Context, key domain.IdempotencyKey, ttl time.Duration) (domain.IdempotencyKey, bool, error)
+	// SaveResponse stores the response of a reserved key.
+	SaveResponse(ctx context.Context, scope, key string, response domain.IdempotentResponse) error
+	// DeleteKey deletes the key if stored, so that the request with the key can run again.
+	DeleteKey(ctx context.Context, scope, key string) error
+	// DeleteExpiredKeys deletes the keys stored longer than ttl ago and returns their number.
+	DeleteExpiredKeys(ctx context.Context, ttl time.Duration) (int64, error)
+}
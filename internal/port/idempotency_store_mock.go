@@ -0,0 +1,130 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package port
+
+import (
+	context "context"
+
+	domain "github.com/nikolayk812/go-tests/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockIdempotencyStore is an autogenerated mock type for the IdempotencyStore type
+type MockIdempotencyStore struct {
+	mock.Mock
+}
+
+// DeleteExpiredKeys provides a mock function with given fields: ctx, ttl
+func (_m *MockIdempotencyStore) DeleteExpiredKeys(ctx context.Context, ttl time.Duration) (int64, error) {
+	ret := _m.Called(ctx, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredKeys")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (int64, error)); ok {
+		return rf(ctx, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int64); ok {
+		r0 = rf(ctx, ttl)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteKey provides a mock function with given fields: ctx, scope, key
+func (_m *MockIdempotencyStore) DeleteKey(ctx context.Context, scope string, key string) error {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveKey provides a mock function with given fields: ctx, key, ttl
+func (_m *MockIdempotencyStore) ReserveKey(ctx context.Context, key domain.IdempotencyKey, ttl time.Duration) (domain.IdempotencyKey, bool, error) {
+	ret := _m.Called(ctx, key, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveKey")
+	}
+
+	var r0 domain.IdempotencyKey
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.IdempotencyKey, time.Duration) (domain.IdempotencyKey, bool, error)); ok {
+		return rf(ctx, key, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.IdempotencyKey, time.Duration) domain.IdempotencyKey); ok {
+		r0 = rf(ctx, key, ttl)
+	} else {
+		r0 = ret.Get(0).(domain.IdempotencyKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.IdempotencyKey, time.Duration) bool); ok {
+		r1 = rf(ctx, key, ttl)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, domain.IdempotencyKey, time.Duration) error); ok {
+		r2 = rf(ctx, key, ttl)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SaveResponse provides a mock function with given fields: ctx, scope, key, response
+func (_m *MockIdempotencyStore) SaveResponse(ctx context.Context, scope string, key string, response domain.IdempotentResponse) error {
+	ret := _m.Called(ctx, scope, key, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveResponse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.IdempotentResponse) error); ok {
+		r0 = rf(ctx, scope, key, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockIdempotencyStore creates a new instance of MockIdempotencyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIdempotencyStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIdempotencyStore {
+	mock := &MockIdempotencyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,10 +2,17 @@ package port
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/nikolayk812/go-tests/internal/domain"
 )
 
+//go:generate mockery --name=OrderRepository --structname=MockOrderRepository --output=. --outpkg=port --filename=order_repository_mock.go
 type OrderRepository interface {
-	GetOrder(ctx context.Context, ownerID string) (domain.Order, error)
-	CreateOrder(ctx context.Context, ownerID string) error
+	GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error)
+	CreateOrder(ctx context.Context, order domain.Order) (uuid.UUID, error)
+	// ListOrders returns up to limit orders of the owner, newest first, starting after the cursor if it is not nil.
+	ListOrders(ctx context.Context, ownerID string, after *domain.OrderCursor, limit int) ([]domain.Order, error)
+	// UpdateOrderStatus returns false if the order does not exist or its status is not change.From.
+	UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, change domain.OrderStatusChange) (bool, error)
+	GetOrderStatusHistory(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error)
 }
@@ -0,0 +1,177 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package port
+
+import (
+	context "context"
+
+	domain "github.com/nikolayk812/go-tests/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockOrderRepository is an autogenerated mock type for the OrderRepository type
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+// CreateOrder provides a mock function with given fields: ctx, order
+func (_m *MockOrderRepository) CreateOrder(ctx context.Context, order domain.Order) (uuid.UUID, error) {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrder")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Order) (uuid.UUID, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Order) uuid.UUID); ok {
+		r0 = rf(ctx, order)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Order) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, orderID
+func (_m *MockOrderRepository) GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Order, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Order); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderStatusHistory provides a mock function with given fields: ctx, orderID
+func (_m *MockOrderRepository) GetOrderStatusHistory(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderStatusHistory")
+	}
+
+	var r0 []domain.OrderStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.OrderStatusChange, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.OrderStatusChange); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.OrderStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrders provides a mock function with given fields: ctx, ownerID, after, limit
+func (_m *MockOrderRepository) ListOrders(ctx context.Context, ownerID string, after *domain.OrderCursor, limit int) ([]domain.Order, error) {
+	ret := _m.Called(ctx, ownerID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrders")
+	}
+
+	var r0 []domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderCursor, int) ([]domain.Order, error)); ok {
+		return rf(ctx, ownerID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderCursor, int) []domain.Order); ok {
+		r0 = rf(ctx, ownerID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.OrderCursor, int) error); ok {
+		r1 = rf(ctx, ownerID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOrderStatus provides a mock function with given fields: ctx, orderID, change
+func (_m *MockOrderRepository) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, change domain.OrderStatusChange) (bool, error) {
+	ret := _m.Called(ctx, orderID, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrderStatus")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OrderStatusChange) (bool, error)); ok {
+		return rf(ctx, orderID, change)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OrderStatusChange) bool); ok {
+		r0 = rf(ctx, orderID, change)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.OrderStatusChange) error); ok {
+		r1 = rf(ctx, orderID, change)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockOrderRepository creates a new instance of MockOrderRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOrderRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOrderRepository {
+	mock := &MockOrderRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+)
+
+//go:generate mockery --name=ProductCatalog --structname=MockProductCatalog --output=. --outpkg=port --filename=product_catalog_mock.go
+type ProductCatalog interface {
+	GetProduct(ctx context.Context, productID uuid.UUID) (domain.Product, error)
+	// GetProducts returns the products found, unknown product IDs are skipped.
+	GetProducts(ctx context.Context, productIDs []uuid.UUID) ([]domain.Product, error)
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package port
+
+import (
+	context "context"
+
+	domain "github.com/nikolayk812/go-tests/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockProductCatalog is an autogenerated mock type for the ProductCatalog type
+type MockProductCatalog struct {
+	mock.Mock
+}
+
+// GetProduct provides a mock function with given fields: ctx, productID
+func (_m *MockProductCatalog) GetProduct(ctx context.Context, productID uuid.UUID) (domain.Product, error) {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProduct")
+	}
+
+	var r0 domain.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Product, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Product); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Get(0).(domain.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProducts provides a mock function with given fields: ctx, productIDs
+func (_m *MockProductCatalog) GetProducts(ctx context.Context, productIDs []uuid.UUID) ([]domain.Product, error) {
+	ret := _m.Called(ctx, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProducts")
+	}
+
+	var r0 []domain.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) ([]domain.Product, error)); ok {
+		return rf(ctx, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) []domain.Product); ok {
+		r0 = rf(ctx, productIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockProductCatalog creates a new instance of MockProductCatalog. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockProductCatalog(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockProductCatalog {
+	mock := &MockProductCatalog{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
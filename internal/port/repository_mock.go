@@ -0,0 +1,439 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package port
+
+import (
+	context "context"
+
+	domain "github.com/nikolayk812/go-tests/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockRepository is an autogenerated mock type for the Repository type
+type MockRepository struct {
+	mock.Mock
+}
+
+// AddItem provides a mock function with given fields: ctx, ownerID, item
+func (_m *MockRepository) AddItem(ctx context.Context, ownerID string, item domain.CartItem) error {
+	ret := _m.Called(ctx, ownerID, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.CartItem) error); ok {
+		r0 = rf(ctx, ownerID, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClearCart provides a mock function with given fields: ctx, ownerID
+func (_m *MockRepository) ClearCart(ctx context.Context, ownerID string) error {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearCart")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrder provides a mock function with given fields: ctx, order
+func (_m *MockRepository) CreateOrder(ctx context.Context, order domain.Order) (uuid.UUID, error) {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrder")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Order) (uuid.UUID, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Order) uuid.UUID); ok {
+		r0 = rf(ctx, order)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Order) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteItem provides a mock function with given fields: ctx, ownerID, productID
+func (_m *MockRepository) DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, ownerID, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItem")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, ownerID, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) bool); ok {
+		r0 = rf(ctx, ownerID, productID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, ownerID, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteItems provides a mock function with given fields: ctx, ownerID, productIDs
+func (_m *MockRepository) DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	ret := _m.Called(ctx, ownerID, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItems")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID) (int, error)); ok {
+		return rf(ctx, ownerID, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID) int); ok {
+		r0 = rf(ctx, ownerID, productIDs)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []uuid.UUID) error); ok {
+		r1 = rf(ctx, ownerID, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCart provides a mock function with given fields: ctx, ownerID
+func (_m *MockRepository) GetCart(ctx context.Context, ownerID string) (domain.Cart, error) {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCart")
+	}
+
+	var r0 domain.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Cart, error)); ok {
+		return rf(ctx, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Cart); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		r0 = ret.Get(0).(domain.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, orderID
+func (_m *MockRepository) GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Order, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Order); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderStatusHistory provides a mock function with given fields: ctx, orderID
+func (_m *MockRepository) GetOrderStatusHistory(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderStatusHistory")
+	}
+
+	var r0 []domain.OrderStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.OrderStatusChange, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.OrderStatusChange); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.OrderStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProduct provides a mock function with given fields: ctx, productID
+func (_m *MockRepository) GetProduct(ctx context.Context, productID uuid.UUID) (domain.Product, error) {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProduct")
+	}
+
+	var r0 domain.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Product, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Product); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Get(0).(domain.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProducts provides a mock function with given fields: ctx, productIDs
+func (_m *MockRepository) GetProducts(ctx context.Context, productIDs []uuid.UUID) ([]domain.Product, error) {
+	ret := _m.Called(ctx, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProducts")
+	}
+
+	var r0 []domain.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) ([]domain.Product, error)); ok {
+		return rf(ctx, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) []domain.Product); ok {
+		r0 = rf(ctx, productIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrders provides a mock function with given fields: ctx, ownerID, after, limit
+func (_m *MockRepository) ListOrders(ctx context.Context, ownerID string, after *domain.OrderCursor, limit int) ([]domain.Order, error) {
+	ret := _m.Called(ctx, ownerID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrders")
+	}
+
+	var r0 []domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderCursor, int) ([]domain.Order, error)); ok {
+		return rf(ctx, ownerID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderCursor, int) []domain.Order); ok {
+		r0 = rf(ctx, ownerID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.OrderCursor, int) error); ok {
+		r1 = rf(ctx, ownerID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockCart provides a mock function with given fields: ctx, ownerID
+func (_m *MockRepository) LockCart(ctx context.Context, ownerID string) (int64, error) {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockCart")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItemPrice provides a mock function with given fields: ctx, ownerID, productID, price
+func (_m *MockRepository) UpdateItemPrice(ctx context.Context, ownerID string, productID uuid.UUID, price domain.Money) (bool, error) {
+	ret := _m.Called(ctx, ownerID, productID, price)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItemPrice")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, domain.Money) (bool, error)); ok {
+		return rf(ctx, ownerID, productID, price)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, domain.Money) bool); ok {
+		r0 = rf(ctx, ownerID, productID, price)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, domain.Money) error); ok {
+		r1 = rf(ctx, ownerID, productID, price)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItemQuantity provides a mock function with given fields: ctx, ownerID, productID, quantity
+func (_m *MockRepository) UpdateItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) (bool, error) {
+	ret := _m.Called(ctx, ownerID, productID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItemQuantity")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, int) (bool, error)); ok {
+		return rf(ctx, ownerID, productID, quantity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, int) bool); ok {
+		r0 = rf(ctx, ownerID, productID, quantity)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, ownerID, productID, quantity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOrderStatus provides a mock function with given fields: ctx, orderID, change
+func (_m *MockRepository) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, change domain.OrderStatusChange) (bool, error) {
+	ret := _m.Called(ctx, orderID, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrderStatus")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OrderStatusChange) (bool, error)); ok {
+		return rf(ctx, orderID, change)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OrderStatusChange) bool); ok {
+		r0 = rf(ctx, orderID, change)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.OrderStatusChange) error); ok {
+		r1 = rf(ctx, orderID, change)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockRepository creates a new instance of MockRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRepository {
+	mock := &MockRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+)
+
+//go:generate mockery --name=Repository --structname=MockRepository --output=. --outpkg=port --filename=repository_mock.go
+type Repository interface {
+	CartRepository
+	OrderRepository
+	ProductCatalog
+}
+
+//go:generate mockery --name=UnitOfWork --structname=MockUnitOfWork --output=. --outpkg=port --filename=unit_of_work_mock.go --inpackage
+type UnitOfWork interface {
+	// WithTx runs fn in a single transaction, repo passed to fn is bound to that transaction.
+	// The transaction is committed when fn returns nil and rolled back otherwise.
+	// fn may run more than once if the transaction is retried, e.g. on a serialization failure.
+	WithTx(ctx context.Context, fn func(repo Repository) error) error
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package port
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockUnitOfWork is an autogenerated mock type for the UnitOfWork type
+type MockUnitOfWork struct {
+	mock.Mock
+}
+
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *MockUnitOfWork) WithTx(ctx context.Context, fn func(Repository) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(Repository) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockUnitOfWork creates a new instance of MockUnitOfWork. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUnitOfWork(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUnitOfWork {
+	mock := &MockUnitOfWork{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,22 +5,31 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nikolayk812/go-tests/internal/domain"
 	"golang.org/x/text/currency"
 )
 
 func (r *repo) GetCart(ctx context.Context, ownerID string) (domain.Cart, error) {
+	ctx = withStatement(ctx, "GetCart")
+
 	var c domain.Cart
 
-	rows, err := r.pool.Query(ctx, "SELECT * FROM cart_items WHERE owner_id = $1", ownerID)
+	// read before the items, so that a concurrent change makes the version older than the items rather than newer,
+	// and a conditional change based on the returned version fails rather than overwrites the unseen change
+	version, err := r.cartVersion(ctx, ownerID)
 	if err != nil {
-		return c, fmt.Errorf("pool.Query: %w", err)
+		return c, fmt.Errorf("r.cartVersion: %w", err)
 	}
 
-	var scannedOwnerID string
+	rows, err := r.db.Query(ctx, `
+			SELECT product_id, price_amount, price_currency, quantity, created_at 
+			FROM cart_items 
+			WHERE owner_id = $1`,
+		ownerID)
+	if err != nil {
+		return c, fmt.Errorf("db.Query: %w", err)
+	}
 
 	cartItems, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.CartItem, error) {
 		var (
@@ -28,7 +37,7 @@ func (r *repo) GetCart(ctx context.Context, ownerID string) (domain.Cart, error)
 			currencyStr string
 		)
 
-		if err := row.Scan(&scannedOwnerID, &item.ProductID, &item.Price.Amount, &currencyStr, &item.CreatedAt); err != nil {
+		if err := row.Scan(&item.ProductID, &item.Price.Amount, &currencyStr, &item.Quantity, &item.CreatedAt); err != nil {
 			return domain.CartItem{}, fmt.Errorf("row.Scan: %w", err)
 		}
 
@@ -48,30 +57,71 @@ func (r *repo) GetCart(ctx context.Context, ownerID string) (domain.Cart, error)
 	return domain.Cart{
 		OwnerID: ownerID,
 		Items:   cartItems,
+		Version: version,
 	}, nil
 }
 
-func (r *repo) AddItem(ctx context.Context, ownerID string, item domain.CartItem) error {
+func (r *repo) cartVersion(ctx context.Context, ownerID string) (int64, error) {
+	var version int64
 
-	_, err := r.pool.Exec(ctx, `
-			INSERT INTO cart_items (owner_id, product_id, price_amount, price_currency) 
-			VALUES ($1, $2, $3, $4)`,
-		ownerID, item.ProductID, item.Price.Amount, item.Price.Currency)
+	err := r.db.QueryRow(ctx, "SELECT version FROM carts WHERE owner_id = $1", ownerID).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			return ErrCartDuplicateItem
-		}
-		return fmt.Errorf("pool.Exec: %w", err)
+		return 0, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return version, nil
+}
+
+// AddItem adds the item to the cart or increments the quantity if the product is already there.
+// ErrCartDuplicateItem is returned if the product is already in the cart with a different price.
+func (r *repo) AddItem(ctx context.Context, ownerID string, item domain.CartItem) error {
+	ctx = withStatement(ctx, "AddItem")
+
+	cmdTag, err := r.db.Exec(ctx, `
+			INSERT INTO cart_items (owner_id, product_id, price_amount, price_currency, quantity) 
+			VALUES ($1, $2, $3, $4, $5) 
+			ON CONFLICT (owner_id, product_id) DO UPDATE 
+			SET quantity = cart_items.quantity + EXCLUDED.quantity 
+			WHERE cart_items.price_amount = EXCLUDED.price_amount 
+			  AND cart_items.price_currency = EXCLUDED.price_currency`,
+		ownerID, item.ProductID, item.Price.Amount, item.Price.Currency, item.Quantity)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	// the conflicting row was not updated because of a different price
+	if cmdTag.RowsAffected() == 0 {
+		return ErrCartDuplicateItem
 	}
 
 	return nil
 }
 
+func (r *repo) UpdateItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) (bool, error) {
+	ctx = withStatement(ctx, "UpdateItemQuantity")
+
+	cmdTag, err := r.db.Exec(ctx, "UPDATE cart_items SET quantity = $3 WHERE owner_id = $1 AND product_id = $2",
+		ownerID, productID, quantity)
+	if err != nil {
+		return false, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (r *repo) DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) (bool, error) {
-	cmdTag, err := r.pool.Exec(ctx, "DELETE FROM cart_items WHERE owner_id = $1 AND product_id = $2", ownerID, productID)
+	ctx = withStatement(ctx, "DeleteItem")
+
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM cart_items WHERE owner_id = $1 AND product_id = $2", ownerID, productID)
 	if err != nil {
-		return false, fmt.Errorf("pool.Exec: %w", err)
+		return false, fmt.Errorf("db.Exec: %w", err)
 	}
 
 	if cmdTag.RowsAffected() == 0 {
@@ -80,3 +130,63 @@ func (r *repo) DeleteItem(ctx context.Context, ownerID string, productID uuid.UU
 
 	return true, nil
 }
+
+func (r *repo) DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	ctx = withStatement(ctx, "DeleteItems")
+
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM cart_items WHERE owner_id = $1 AND product_id = ANY($2)", ownerID, productIDs)
+	if err != nil {
+		return 0, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return int(cmdTag.RowsAffected()), nil
+}
+
+func (r *repo) ClearCart(ctx context.Context, ownerID string) error {
+	ctx = withStatement(ctx, "ClearCart")
+
+	if _, err := r.db.Exec(ctx, "DELETE FROM cart_items WHERE owner_id = $1", ownerID); err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) UpdateItemPrice(ctx context.Context, ownerID string, productID uuid.UUID, price domain.Money) (bool, error) {
+	ctx = withStatement(ctx, "UpdateItemPrice")
+
+	cmdTag, err := r.db.Exec(ctx, `
+			UPDATE cart_items SET price_amount = $3, price_currency = $4 
+			WHERE owner_id = $1 AND product_id = $2`,
+		ownerID, productID, price.Amount, price.Currency)
+	if err != nil {
+		return false, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// LockCart locks the cart row until the end of the transaction, the row is created for a cart which never had items,
+// so that concurrent first changes of the cart are serialized as well.
+func (r *repo) LockCart(ctx context.Context, ownerID string) (int64, error) {
+	ctx = withStatement(ctx, "LockCart")
+
+	var version int64
+
+	err := r.db.QueryRow(ctx, `
+			INSERT INTO carts (owner_id, version) 
+			VALUES ($1, 0) 
+			ON CONFLICT (owner_id) DO UPDATE 
+			SET version = carts.version 
+			RETURNING version`,
+		ownerID).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return version, nil
+}
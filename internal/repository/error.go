@@ -4,4 +4,12 @@ import "errors"
 
 var (
 	ErrCartDuplicateItem = errors.New("duplicate cart item")
+	ErrOrderNotFound     = errors.New("order not found")
+
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	ErrProductNotFound      = errors.New("product not found")
+
+	ErrSchemaNotMigrated = errors.New("schema not migrated to the latest version")
+
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
 )
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"golang.org/x/text/currency"
+)
+
+// GetRate returns the stored rate for the currency pair,
+// a rate stored for the opposite direction is inverted.
+func (r *repo) GetRate(ctx context.Context, from, to currency.Unit) (domain.ExchangeRate, error) {
+	ctx = withStatement(ctx, "GetRate")
+
+	var (
+		rate              domain.ExchangeRate
+		baseStr, quoteStr string
+	)
+
+	// the direct rate goes first if both directions are stored
+	err := r.db.QueryRow(ctx, `
+			SELECT base_currency, quote_currency, rate, updated_at 
+			FROM exchange_rates 
+			WHERE (base_currency = $1 AND quote_currency = $2) 
+			   OR (base_currency = $2 AND quote_currency = $1) 
+			ORDER BY base_currency = $1 DESC 
+			LIMIT 1`,
+		from.String(), to.String()).
+		Scan(&baseStr, &quoteStr, &rate.Rate, &rate.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rate, ErrExchangeRateNotFound
+		}
+		return rate, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	if rate.From, err = currency.ParseISO(baseStr); err != nil {
+		return rate, fmt.Errorf("currency.ParseISO[%s]: %w", baseStr, err)
+	}
+
+	if rate.To, err = currency.ParseISO(quoteStr); err != nil {
+		return rate, fmt.Errorf("currency.ParseISO[%s]: %w", quoteStr, err)
+	}
+
+	if rate.From != from {
+		rate = rate.Invert()
+	}
+
+	return rate, nil
+}
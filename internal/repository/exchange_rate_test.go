@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+	"golang.org/x/text/currency"
+	"testing"
+)
+
+type exchangeRateRepositorySuite struct {
+	postgresSuite
+}
+
+// entry point to run the tests in the suite
+func TestExchangeRateRepositorySuite(t *testing.T) {
+	// Verifies no leaks after all tests in the suite run.
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(exchangeRateRepositorySuite))
+}
+
+func (suite *exchangeRateRepositorySuite) TestGetRate() {
+	t := suite.T()
+	ctx := t.Context()
+
+	_, err := suite.pool.Exec(ctx, `
+			INSERT INTO exchange_rates (base_currency, quote_currency, rate) 
+			VALUES ('EUR', 'USD', 1.25), ('GBP', 'EUR', 1.2), ('EUR', 'GBP', 0.8)`)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		from      currency.Unit
+		to        currency.Unit
+		wantRate  string
+		wantError error
+	}{
+		{
+			name:     "direct rate",
+			from:     currency.EUR,
+			to:       currency.USD,
+			wantRate: "1.25",
+		},
+		{
+			name:     "inverted rate",
+			from:     currency.USD,
+			to:       currency.EUR,
+			wantRate: "0.8",
+		},
+		{
+			name:     "direct rate preferred over inverted",
+			from:     currency.EUR,
+			to:       currency.GBP,
+			wantRate: "0.8",
+		},
+		{
+			name:      "unknown pair",
+			from:      currency.USD,
+			to:        currency.JPY,
+			wantError: repository.ErrExchangeRateNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			t := suite.T()
+
+			rate, err := suite.repo.GetRate(t.Context(), tc.from, tc.to)
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.from, rate.From)
+			assert.Equal(t, tc.to, rate.To)
+			assert.True(t, decimal.RequireFromString(tc.wantRate).Equal(rate.Rate), "got rate %s", rate.Rate)
+			assert.False(t, rate.UpdatedAt.IsZero())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"io/fs"
+	"sync"
+)
+
+// latestMigration is the version of the last embedded migration, the embedded files never change.
+var latestMigration = sync.OnceValues(func() (uint, error) {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("iofs.New: %w", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("source.First: %w", err)
+	}
+
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("source.Next[%d]: %w", version, err)
+		}
+
+		version = next
+	}
+})
+
+// CheckSchema returns an error unless the database is migrated to the latest embedded migration,
+// e.g. while a deployment still runs the migrations or after a failed migration left the database dirty.
+func CheckSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	latest, err := latestMigration()
+	if err != nil {
+		return fmt.Errorf("latestMigration: %w", err)
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+
+	// the table golang-migrate keeps the current version in
+	err = pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrSchemaNotMigrated
+	}
+	if err != nil {
+		return fmt.Errorf("row.Scan: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("%w: version %d is dirty", ErrSchemaNotMigrated, version)
+	}
+
+	if uint(version) != latest {
+		return fmt.Errorf("%w: version %d, latest %d", ErrSchemaNotMigrated, version, latest)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"time"
+)
+
+// ReserveKey expires the keys by the database clock, which also sets created_at.
+func (r *repo) ReserveKey(ctx context.Context, key domain.IdempotencyKey, ttl time.Duration) (domain.IdempotencyKey, bool, error) {
+	ctx = withStatement(ctx, "ReserveKey")
+
+	stored, reserved, err := r.reserveKey(ctx, key, ttl)
+	if errors.Is(err, ErrIdempotencyKeyNotFound) {
+		// the stored key was purged or released after the insert conflicted with it, so it is free again
+		stored, reserved, err = r.reserveKey(ctx, key, ttl)
+	}
+
+	return stored, reserved, err
+}
+
+// reserveKey returns ErrIdempotencyKeyNotFound if the stored key is deleted before it is read.
+func (r *repo) reserveKey(ctx context.Context, key domain.IdempotencyKey, ttl time.Duration) (domain.IdempotencyKey, bool, error) {
+	reserved := key
+	reserved.Response = nil
+
+	// the update applies to an expired key only, otherwise no row is returned
+	err := r.db.QueryRow(ctx, `
+			INSERT INTO idempotency_keys (scope, key, fingerprint) 
+			VALUES ($1, $2, $3) 
+			ON CONFLICT (scope, key) DO UPDATE 
+			SET fingerprint = EXCLUDED.fingerprint, 
+			    status_code = NULL, 
+			    response_header = NULL, 
+			    response_body = NULL, 
+			    created_at = CURRENT_TIMESTAMP 
+			WHERE idempotency_keys.created_at < CURRENT_TIMESTAMP - $4::INTERVAL 
+			RETURNING created_at`,
+		key.Scope, key.Key, key.Fingerprint, ttl).
+		Scan(&reserved.CreatedAt)
+	if err == nil {
+		return reserved, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.IdempotencyKey{}, false, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	stored, err := r.getKey(ctx, key.Scope, key.Key)
+	if err != nil {
+		return domain.IdempotencyKey{}, false, fmt.Errorf("r.getKey: %w", err)
+	}
+
+	return stored, false, nil
+}
+
+func (r *repo) getKey(ctx context.Context, scope, key string) (domain.IdempotencyKey, error) {
+	stored := domain.IdempotencyKey{Scope: scope, Key: key}
+
+	var (
+		statusCode *int
+		header     map[string]string
+		body       []byte
+	)
+
+	err := r.db.QueryRow(ctx, `
+			SELECT fingerprint, status_code, response_header, response_body, created_at 
+			FROM idempotency_keys 
+			WHERE scope = $1 AND key = $2`,
+		scope, key).
+		Scan(&stored.Fingerprint, &statusCode, &header, &body, &stored.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return stored, ErrIdempotencyKeyNotFound
+		}
+		return stored, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	if statusCode != nil {
+		stored.Response = &domain.IdempotentResponse{
+			StatusCode: *statusCode,
+			Header:     header,
+			Body:       body,
+		}
+	}
+
+	return stored, nil
+}
+
+func (r *repo) SaveResponse(ctx context.Context, scope, key string, response domain.IdempotentResponse) error {
+	ctx = withStatement(ctx, "SaveResponse")
+
+	cmdTag, err := r.db.Exec(ctx, `
+			UPDATE idempotency_keys 
+			SET status_code = $3, response_header = $4, response_body = $5 
+			WHERE scope = $1 AND key = $2`,
+		scope, key, response.StatusCode, response.Header, response.Body)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) DeleteKey(ctx context.Context, scope, key string) error {
+	ctx = withStatement(ctx, "DeleteKey")
+
+	if _, err := r.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2", scope, key); err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) DeleteExpiredKeys(ctx context.Context, ttl time.Duration) (int64, error) {
+	ctx = withStatement(ctx, "DeleteExpiredKeys")
+
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE created_at < CURRENT_TIMESTAMP - $1::INTERVAL", ttl)
+	if err != nil {
+		return 0, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return cmdTag.RowsAffected(), nil
+}
@@ -0,0 +1,178 @@
+package repository_test
+
+import (
+	"github.com/brianvoe/gofakeit"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const idempotencyTTL = time.Hour
+
+type idempotencyStoreSuite struct {
+	postgresSuite
+}
+
+// entry point to run the tests in the suite
+func TestIdempotencyStoreSuite(t *testing.T) {
+	// Verifies no leaks after all tests in the suite run.
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(idempotencyStoreSuite))
+}
+
+func (suite *idempotencyStoreSuite) TestReserveKey() {
+	t := suite.T()
+	ctx := t.Context()
+
+	key := fakeIdempotencyKey()
+
+	reserved, ok, err := suite.repo.ReserveKey(ctx, key, idempotencyTTL)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, key.Fingerprint, reserved.Fingerprint)
+	assert.Nil(t, reserved.Response)
+	assert.False(t, reserved.CreatedAt.IsZero())
+
+	// in progress
+	retried := key
+	retried.Fingerprint = gofakeit.UUID()
+
+	stored, ok, err := suite.repo.ReserveKey(ctx, retried, idempotencyTTL)
+	require.NoError(t, err)
+	require.False(t, ok)
+	assert.Equal(t, key.Fingerprint, stored.Fingerprint)
+	assert.Nil(t, stored.Response)
+
+	response := domain.IdempotentResponse{
+		StatusCode: http.StatusCreated,
+		Header:     map[string]string{"Content-Type": "application/json"},
+		Body:       []byte(`{"deleted":1}`),
+	}
+	require.NoError(t, suite.repo.SaveResponse(ctx, key.Scope, key.Key, response))
+
+	stored, ok, err = suite.repo.ReserveKey(ctx, key, idempotencyTTL)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NotNil(t, stored.Response)
+	assert.Equal(t, response, *stored.Response)
+
+	// the same key of another scope is a different key
+	otherScope := key
+	otherScope.Scope = gofakeit.Username()
+
+	_, ok, err = suite.repo.ReserveKey(ctx, otherScope, idempotencyTTL)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func (suite *idempotencyStoreSuite) TestReserveKey_Expired() {
+	t := suite.T()
+	ctx := t.Context()
+
+	key := fakeIdempotencyKey()
+
+	_, ok, err := suite.repo.ReserveKey(ctx, key, idempotencyTTL)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, suite.repo.SaveResponse(ctx, key.Scope, key.Key, domain.IdempotentResponse{StatusCode: http.StatusNoContent}))
+
+	suite.ageKey(key, 2*idempotencyTTL)
+
+	retried := key
+	retried.Fingerprint = gofakeit.UUID()
+
+	reserved, ok, err := suite.repo.ReserveKey(ctx, retried, idempotencyTTL)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, retried.Fingerprint, reserved.Fingerprint)
+
+	stored, ok, err := suite.repo.ReserveKey(ctx, key, idempotencyTTL)
+	require.NoError(t, err)
+	require.False(t, ok)
+	assert.Equal(t, retried.Fingerprint, stored.Fingerprint)
+	assert.Nil(t, stored.Response)
+}
+
+func (suite *idempotencyStoreSuite) TestSaveResponse_NotFound() {
+	t := suite.T()
+
+	key := fakeIdempotencyKey()
+
+	err := suite.repo.SaveResponse(t.Context(), key.Scope, key.Key, domain.IdempotentResponse{StatusCode: http.StatusOK})
+	require.ErrorIs(t, err, repository.ErrIdempotencyKeyNotFound)
+}
+
+func (suite *idempotencyStoreSuite) TestDeleteKey() {
+	t := suite.T()
+	ctx := t.Context()
+
+	key := fakeIdempotencyKey()
+
+	_, ok, err := suite.repo.ReserveKey(ctx, key, idempotencyTTL)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, suite.repo.DeleteKey(ctx, key.Scope, key.Key))
+	// deleting a missing key is not an error
+	require.NoError(t, suite.repo.DeleteKey(ctx, key.Scope, key.Key))
+
+	_, ok, err = suite.repo.ReserveKey(ctx, key, idempotencyTTL)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func (suite *idempotencyStoreSuite) TestDeleteExpiredKeys() {
+	t := suite.T()
+	ctx := t.Context()
+
+	expired := fakeIdempotencyKey()
+	live := fakeIdempotencyKey()
+
+	for _, key := range []domain.IdempotencyKey{expired, live} {
+		_, ok, err := suite.repo.ReserveKey(ctx, key, idempotencyTTL)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	suite.ageKey(expired, 2*idempotencyTTL)
+
+	deleted, err := suite.repo.DeleteExpiredKeys(ctx, idempotencyTTL)
+	require.NoError(t, err)
+	// other tests of the suite may leave expired keys behind
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	_, ok, err := suite.repo.ReserveKey(ctx, expired, idempotencyTTL)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = suite.repo.ReserveKey(ctx, live, idempotencyTTL)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// ageKey moves the creation of the key back by age.
+func (suite *idempotencyStoreSuite) ageKey(key domain.IdempotencyKey, age time.Duration) {
+	t := suite.T()
+
+	_, err := suite.pool.Exec(t.Context(), `
+			UPDATE idempotency_keys 
+			SET created_at = created_at - $3::INTERVAL 
+			WHERE scope = $1 AND key = $2`,
+		key.Scope, key.Key, age)
+	require.NoError(t, err)
+}
+
+func fakeIdempotencyKey() domain.IdempotencyKey {
+	return domain.IdempotencyKey{
+		Scope:       gofakeit.Username(),
+		Key:         gofakeit.UUID(),
+		Fingerprint: gofakeit.UUID(),
+	}
+}
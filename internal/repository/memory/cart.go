@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+// errQuantityNotPositive stands for the violated quantity > 0 check constraint of the Postgres repository.
+var errQuantityNotPositive = errors.New("quantity must be positive")
+
+// cartRepo is a database-free port.CartRepository with the same semantics as the Postgres repository,
+// it is meant for unit tests and local development.
+type cartRepo struct {
+	mu       sync.Mutex
+	carts    map[string][]domain.CartItem // by owner ID
+	versions map[string]int64             // by owner ID, incremented per changed item as in Postgres
+}
+
+func NewCart() port.CartRepository {
+	return &cartRepo{
+		carts:    make(map[string][]domain.CartItem),
+		versions: make(map[string]int64),
+	}
+}
+
+func (r *cartRepo) GetCart(_ context.Context, ownerID string) (domain.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// copied, so that callers can not modify the stored items
+	items := make([]domain.CartItem, len(r.carts[ownerID]))
+	copy(items, r.carts[ownerID])
+
+	return domain.Cart{
+		OwnerID: ownerID,
+		Items:   items,
+		Version: r.versions[ownerID],
+	}, nil
+}
+
+// AddItem adds the item to the cart or increments the quantity if the product is already there.
+// repository.ErrCartDuplicateItem is returned if the product is already in the cart with a different price.
+func (r *cartRepo) AddItem(_ context.Context, ownerID string, item domain.CartItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// checked before the quantities are summed up, as in Postgres
+	if item.Quantity <= 0 {
+		return fmt.Errorf("%w: %d", errQuantityNotPositive, item.Quantity)
+	}
+
+	items := r.carts[ownerID]
+
+	if i := r.indexOf(ownerID, item.ProductID); i >= 0 {
+		if !items[i].Price.Equal(item.Price) {
+			return repository.ErrCartDuplicateItem
+		}
+
+		items[i].Quantity += item.Quantity
+		r.versions[ownerID]++
+
+		return nil
+	}
+
+	item.CreatedAt = time.Now()
+	item.PriceChange = nil
+
+	r.carts[ownerID] = append(items, item)
+	r.versions[ownerID]++
+
+	return nil
+}
+
+func (r *cartRepo) DeleteItem(_ context.Context, ownerID string, productID uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ownerID, productID)
+	if i < 0 {
+		return false, nil
+	}
+
+	r.carts[ownerID] = slices.Delete(r.carts[ownerID], i, i+1)
+	r.versions[ownerID]++
+
+	return true, nil
+}
+
+func (r *cartRepo) DeleteItems(_ context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := r.carts[ownerID]
+	before := len(items)
+
+	r.carts[ownerID] = slices.DeleteFunc(items, func(item domain.CartItem) bool {
+		return slices.Contains(productIDs, item.ProductID)
+	})
+
+	deleted := before - len(r.carts[ownerID])
+	r.versions[ownerID] += int64(deleted)
+
+	return deleted, nil
+}
+
+func (r *cartRepo) ClearCart(_ context.Context, ownerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.versions[ownerID] += int64(len(r.carts[ownerID]))
+	delete(r.carts, ownerID)
+
+	return nil
+}
+
+func (r *cartRepo) UpdateItemQuantity(_ context.Context, ownerID string, productID uuid.UUID, quantity int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ownerID, productID)
+	if i < 0 {
+		return false, nil
+	}
+
+	if quantity <= 0 {
+		return false, fmt.Errorf("%w: %d", errQuantityNotPositive, quantity)
+	}
+
+	r.carts[ownerID][i].Quantity = quantity
+	r.versions[ownerID]++
+
+	return true, nil
+}
+
+func (r *cartRepo) UpdateItemPrice(_ context.Context, ownerID string, productID uuid.UUID, price domain.Money) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ownerID, productID)
+	if i < 0 {
+		return false, nil
+	}
+
+	r.carts[ownerID][i].Price = price
+	r.versions[ownerID]++
+
+	return true, nil
+}
+
+// LockCart returns the cart version, the repository has no transactions to keep the cart locked in.
+func (r *cartRepo) LockCart(_ context.Context, ownerID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.versions[ownerID], nil
+}
+
+// indexOf returns the index of the product in the cart or -1, the caller must hold the lock.
+func (r *cartRepo) indexOf(ownerID string, productID uuid.UUID) int {
+	return slices.IndexFunc(r.carts[ownerID], func(item domain.CartItem) bool {
+		return item.ProductID == productID
+	})
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"github.com/nikolayk812/go-tests/internal/repository/memory"
+	"github.com/nikolayk812/go-tests/internal/repository/repotest"
+	"go.uber.org/goleak"
+	"testing"
+)
+
+func TestCartRepository(t *testing.T) {
+	// Verifies no leaks after all tests in the suite run.
+	defer goleak.VerifyNone(t)
+
+	repotest.RunCartRepositorySuite(t, memory.NewCart())
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// NewMigrate returns a migrate instance running the embedded migrations against the database,
+// the caller must close it.
+func NewMigrate(connStr string) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("iofs.New: %w", err)
+	}
+
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open: %w", err)
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("postgres.WithInstance: %w", err), db.Close())
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("migrate.NewWithInstance: %w", err), driver.Close())
+	}
+
+	return m, nil
+}
+
+// MigrateUp applies all the up migrations, an up to date database is not an error.
+func MigrateUp(connStr string) (err error) {
+	m, err := NewMigrate(connStr)
+	if err != nil {
+		return fmt.Errorf("NewMigrate: %w", err)
+	}
+	defer func() {
+		srcErr, dbErr := m.Close()
+		err = errors.Join(err, srcErr, dbErr)
+	}()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("m.Up: %w", err)
+	}
+
+	return nil
+}
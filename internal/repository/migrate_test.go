@@ -0,0 +1,109 @@
+package repository_test
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+	"testing"
+)
+
+type migrateSuite struct {
+	postgresSuite
+}
+
+// entry point to run the tests in the suite
+func TestMigrateSuite(t *testing.T) {
+	// Verifies no leaks after all tests in the suite run.
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(migrateSuite))
+}
+
+func (suite *migrateSuite) TestUpDownUp() {
+	t := suite.T()
+
+	allTables := []string{
+		"cart_items",
+		"carts",
+		"exchange_rates",
+		"idempotency_keys",
+		"order_items",
+		"order_status_history",
+		"orders",
+		"products",
+	}
+
+	m, err := repository.NewMigrate(suite.connStr)
+	require.NoError(t, err)
+	defer func() {
+		srcErr, dbErr := m.Close()
+		assert.NoError(t, srcErr)
+		assert.NoError(t, dbErr)
+	}()
+
+	// migrated up by the suite setup
+	upVersion, dirty, err := m.Version()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	assert.Equal(t, allTables, suite.tables())
+
+	require.NoError(t, m.Down())
+	assert.Empty(t, suite.tables())
+
+	require.NoError(t, m.Up())
+
+	version, dirty, err := m.Version()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	assert.Equal(t, upVersion, version)
+	assert.Equal(t, allTables, suite.tables())
+}
+
+// tables returns the application tables, the migrate bookkeeping table is excluded.
+func (suite *migrateSuite) tables() []string {
+	t := suite.T()
+	ctx := t.Context()
+
+	rows, err := suite.pool.Query(ctx, `
+			SELECT table_name 
+			FROM information_schema.tables 
+			WHERE table_schema = 'public' AND table_name <> 'schema_migrations' 
+			ORDER BY table_name`)
+	require.NoError(t, err)
+
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+
+	return tables
+}
+
+func (suite *migrateSuite) TestCheckSchema() {
+	t := suite.T()
+	ctx := t.Context()
+
+	m, err := repository.NewMigrate(suite.connStr)
+	require.NoError(t, err)
+	defer func() {
+		srcErr, dbErr := m.Close()
+		assert.NoError(t, srcErr)
+		assert.NoError(t, dbErr)
+	}()
+
+	// migrated up by the suite setup
+	require.NoError(t, repository.CheckSchema(ctx, suite.pool))
+
+	upVersion, _, err := m.Version()
+	require.NoError(t, err)
+
+	require.NoError(t, m.Migrate(upVersion-1))
+	assert.ErrorIs(t, repository.CheckSchema(ctx, suite.pool), repository.ErrSchemaNotMigrated)
+
+	require.NoError(t, m.Down())
+	assert.ErrorIs(t, repository.CheckSchema(ctx, suite.pool), repository.ErrSchemaNotMigrated)
+
+	require.NoError(t, m.Up())
+	assert.NoError(t, repository.CheckSchema(ctx, suite.pool))
+}
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS orders
+(
+    id         UUID      DEFAULT gen_random_uuid() NOT NULL,
+    owner_id   VARCHAR(255)                        NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_orders_owner ON orders (owner_id);
+
+CREATE TABLE IF NOT EXISTS order_items
+(
+    order_id       UUID                                NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    product_id     UUID                                NOT NULL,
+    price_amount   DECIMAL                             NOT NULL,
+    price_currency VARCHAR(3)                          NOT NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (order_id, product_id)
+);
@@ -0,0 +1,21 @@
+ALTER TABLE orders
+    ADD COLUMN status VARCHAR(16) DEFAULT 'pending' NOT NULL
+        CHECK (status IN ('pending', 'paid', 'shipped', 'delivered', 'cancelled'));
+
+CREATE TABLE IF NOT EXISTS order_status_history
+(
+    id          BIGSERIAL                           NOT NULL,
+    order_id    UUID                                NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    from_status VARCHAR(16),
+    to_status   VARCHAR(16)                         NOT NULL,
+    changed_by  VARCHAR(255)                        NOT NULL,
+    changed_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_order_status_history_order ON order_status_history (order_id);
+
+-- orders created before the status existed start their history as pending
+INSERT INTO order_status_history (order_id, to_status, changed_by, changed_at)
+SELECT id, 'pending', owner_id, created_at
+FROM orders;
@@ -0,0 +1,5 @@
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE cart_items
+    DROP COLUMN IF EXISTS quantity;
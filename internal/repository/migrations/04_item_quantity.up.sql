@@ -0,0 +1,5 @@
+ALTER TABLE cart_items
+    ADD COLUMN quantity INT DEFAULT 1 NOT NULL CHECK (quantity > 0);
+
+ALTER TABLE order_items
+    ADD COLUMN quantity INT DEFAULT 1 NOT NULL CHECK (quantity > 0);
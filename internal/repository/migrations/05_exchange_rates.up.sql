@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS exchange_rates
+(
+    base_currency  VARCHAR(3)                          NOT NULL,
+    quote_currency VARCHAR(3)                          NOT NULL,
+    rate           DECIMAL                             NOT NULL CHECK (rate > 0),
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (base_currency, quote_currency)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS products
+(
+    id             UUID                                NOT NULL,
+    name           VARCHAR(255)                        NOT NULL,
+    price_amount   DECIMAL                             NOT NULL CHECK (price_amount >= 0),
+    price_currency VARCHAR(3)                          NOT NULL,
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (id)
+);
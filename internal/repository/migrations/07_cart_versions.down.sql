@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS cart_items_version ON cart_items;
+DROP FUNCTION IF EXISTS increment_cart_version();
+DROP TABLE IF EXISTS carts;
@@ -0,0 +1,39 @@
+-- the version of a cart changes with every change of its items, a missing cart has version 0
+CREATE TABLE IF NOT EXISTS carts
+(
+    owner_id VARCHAR(255) NOT NULL,
+    version  BIGINT       NOT NULL CHECK (version >= 0),
+    PRIMARY KEY (owner_id)
+);
+
+-- the cart items are changed by single statements, so the version is maintained by the database
+-- rather than by a second statement of every repository method
+CREATE OR REPLACE FUNCTION increment_cart_version() RETURNS TRIGGER AS
+$$
+DECLARE
+    changed_owner_id VARCHAR(255);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_owner_id := OLD.owner_id;
+    ELSE
+        changed_owner_id := NEW.owner_id;
+    END IF;
+
+    INSERT INTO carts (owner_id, version)
+    VALUES (changed_owner_id, 1)
+    ON CONFLICT (owner_id) DO UPDATE SET version = carts.version + 1;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER cart_items_version
+    AFTER INSERT OR UPDATE OR DELETE
+    ON cart_items
+    FOR EACH ROW
+EXECUTE FUNCTION increment_cart_version();
+
+-- carts created before the versions existed start at version 1
+INSERT INTO carts (owner_id, version)
+SELECT DISTINCT owner_id, 1
+FROM cart_items;
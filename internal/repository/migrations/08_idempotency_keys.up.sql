@@ -0,0 +1,16 @@
+-- the Idempotency-Key header of a request and the response replayed on its retries,
+-- the response columns are NULL while the request is in progress
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    scope           VARCHAR(255)                        NOT NULL,
+    key             VARCHAR(255)                        NOT NULL,
+    fingerprint     VARCHAR(64)                         NOT NULL,
+    status_code     INT CHECK (status_code BETWEEN 100 AND 599),
+    response_header JSONB,
+    response_body   BYTEA,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+-- the expired keys are purged periodically
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at);
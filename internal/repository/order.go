@@ -2,17 +2,242 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/nikolayk812/go-tests/internal/domain"
+	"golang.org/x/text/currency"
 )
 
-func (r *repo) GetOrder(ctx context.Context, orderID string) (domain.Order, error) {
-	// TODO: implement
+func (r *repo) GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	ctx = withStatement(ctx, "GetOrder")
 
-	return domain.Order{}, nil
+	var o domain.Order
+
+	err := r.db.QueryRow(ctx, "SELECT id, owner_id, status, created_at FROM orders WHERE id = $1", orderID).
+		Scan(&o.ID, &o.OwnerID, &o.Status, &o.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return o, ErrOrderNotFound
+		}
+		return o, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	items, err := r.getOrderItems(ctx, []uuid.UUID{orderID})
+	if err != nil {
+		return o, fmt.Errorf("r.getOrderItems: %w", err)
+	}
+
+	o.Items = items[orderID]
+
+	return o, nil
+}
+
+func (r *repo) ListOrders(ctx context.Context, ownerID string, after *domain.OrderCursor, limit int) ([]domain.Order, error) {
+	ctx = withStatement(ctx, "ListOrders")
+
+	var (
+		rows pgx.Rows
+		err  error
+	)
+
+	if after == nil {
+		rows, err = r.db.Query(ctx, `
+				SELECT id, owner_id, status, created_at 
+				FROM orders 
+				WHERE owner_id = $1 
+				ORDER BY created_at DESC, id DESC 
+				LIMIT $2`,
+			ownerID, limit)
+	} else {
+		rows, err = r.db.Query(ctx, `
+				SELECT id, owner_id, status, created_at 
+				FROM orders 
+				WHERE owner_id = $1 AND (created_at, id) < ($2, $3) 
+				ORDER BY created_at DESC, id DESC 
+				LIMIT $4`,
+			ownerID, after.CreatedAt, after.ID, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+
+	orders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Order, error) {
+		var o domain.Order
+
+		if err := row.Scan(&o.ID, &o.OwnerID, &o.Status, &o.CreatedAt); err != nil {
+			return domain.Order{}, fmt.Errorf("row.Scan: %w", err)
+		}
+
+		return o, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
+	}
+
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	orderIDs := make([]uuid.UUID, 0, len(orders))
+	for _, o := range orders {
+		orderIDs = append(orderIDs, o.ID)
+	}
+
+	items, err := r.getOrderItems(ctx, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("r.getOrderItems: %w", err)
+	}
+
+	for i := range orders {
+		orders[i].Items = items[orders[i].ID]
+	}
+
+	return orders, nil
+}
+
+// CreateOrder persists the order together with its items in a single transaction.
+// Item prices are stored as snapshots, so later price changes do not affect the order.
+func (r *repo) CreateOrder(ctx context.Context, order domain.Order) (uuid.UUID, error) {
+	ctx = withStatement(ctx, "CreateOrder")
+
+	var orderID uuid.UUID
+
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, "INSERT INTO orders (owner_id, status) VALUES ($1, $2) RETURNING id",
+			order.OwnerID, domain.OrderStatusPending).Scan(&orderID); err != nil {
+			return fmt.Errorf("row.Scan: %w", err)
+		}
+
+		_, err := tx.Exec(ctx, `
+				INSERT INTO order_status_history (order_id, to_status, changed_by) 
+				VALUES ($1, $2, $3)`,
+			orderID, domain.OrderStatusPending, order.OwnerID)
+		if err != nil {
+			return fmt.Errorf("tx.Exec: %w", err)
+		}
+
+		for _, item := range order.Items {
+			_, err := tx.Exec(ctx, `
+					INSERT INTO order_items (order_id, product_id, price_amount, price_currency, quantity) 
+					VALUES ($1, $2, $3, $4, $5)`,
+				orderID, item.ProductID, item.Price.Amount, item.Price.Currency, item.Quantity)
+			if err != nil {
+				return fmt.Errorf("tx.Exec: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("pgx.BeginFunc: %w", err)
+	}
+
+	return orderID, nil
+}
+
+// UpdateOrderStatus moves the order from change.From to change.To and records the change in the history.
+// It returns false if the order does not exist or its status is not change.From anymore.
+func (r *repo) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, change domain.OrderStatusChange) (bool, error) {
+	ctx = withStatement(ctx, "UpdateOrderStatus")
+
+	var updated bool
+
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, "UPDATE orders SET status = $3 WHERE id = $1 AND status = $2",
+			orderID, change.From, change.To)
+		if err != nil {
+			return fmt.Errorf("tx.Exec: %w", err)
+		}
+
+		if cmdTag.RowsAffected() == 0 {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `
+				INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) 
+				VALUES ($1, $2, $3, $4)`,
+			orderID, change.From, change.To, change.ChangedBy)
+		if err != nil {
+			return fmt.Errorf("tx.Exec: %w", err)
+		}
+
+		updated = true
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("pgx.BeginFunc: %w", err)
+	}
+
+	return updated, nil
 }
 
-func (r *repo) CreateOrder(ctx context.Context, orderID string) error {
-	// TODO: implement
+func (r *repo) GetOrderStatusHistory(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	ctx = withStatement(ctx, "GetOrderStatusHistory")
+
+	rows, err := r.db.Query(ctx, `
+			SELECT COALESCE(from_status, ''), to_status, changed_by, changed_at 
+			FROM order_status_history 
+			WHERE order_id = $1 
+			ORDER BY changed_at, id`,
+		orderID)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+
+	history, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.OrderStatusChange, error) {
+		var change domain.OrderStatusChange
+
+		if err := row.Scan(&change.From, &change.To, &change.ChangedBy, &change.ChangedAt); err != nil {
+			return domain.OrderStatusChange{}, fmt.Errorf("row.Scan: %w", err)
+		}
+
+		return change, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
+	}
+
+	return history, nil
+}
+
+// getOrderItems returns items of the given orders grouped by order ID.
+func (r *repo) getOrderItems(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]domain.OrderItem, error) {
+	rows, err := r.db.Query(ctx, `
+			SELECT order_id, product_id, price_amount, price_currency, quantity, created_at 
+			FROM order_items 
+			WHERE order_id = ANY($1) 
+			ORDER BY created_at, product_id`,
+		orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+
+	items := make(map[uuid.UUID][]domain.OrderItem, len(orderIDs))
+
+	var (
+		orderID     uuid.UUID
+		item        domain.OrderItem
+		currencyStr string
+	)
+
+	_, err = pgx.ForEachRow(rows, []any{&orderID, &item.ProductID, &item.Price.Amount, &currencyStr, &item.Quantity, &item.CreatedAt}, func() error {
+		currencyUnit, err := currency.ParseISO(currencyStr)
+		if err != nil {
+			return fmt.Errorf("currency.ParseISO[%s]: %w", currencyStr, err)
+		}
+
+		item.Price.Currency = currencyUnit
+
+		items[orderID] = append(items[orderID], item)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pgx.ForEachRow: %w", err)
+	}
 
-	return nil
+	return items, nil
 }
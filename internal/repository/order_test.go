@@ -0,0 +1,216 @@
+package repository_test
+
+import (
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/nikolayk812/go-tests/internal/repository/repotest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+	"golang.org/x/text/currency"
+	"testing"
+)
+
+type orderRepositorySuite struct {
+	postgresSuite
+}
+
+// entry point to run the tests in the suite
+func TestOrderRepositorySuite(t *testing.T) {
+	// Verifies no leaks after all tests in the suite run.
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(orderRepositorySuite))
+}
+
+func (suite *orderRepositorySuite) TestCreateOrder() {
+	item1 := fakeOrderItem()
+	item2 := fakeOrderItem()
+
+	testCases := []struct {
+		name  string
+		items []domain.OrderItem
+	}{
+		{
+			name: "no items: ok",
+		},
+		{
+			name:  "single item: ok",
+			items: []domain.OrderItem{item1},
+		},
+		{
+			name:  "two items: ok",
+			items: []domain.OrderItem{item1, item2},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			t := suite.T()
+			ctx := t.Context()
+
+			order := domain.Order{
+				OwnerID: gofakeit.UUID(),
+				Items:   tc.items,
+			}
+
+			orderID, err := suite.repo.CreateOrder(ctx, order)
+			require.NoError(t, err)
+			require.NotEqual(t, uuid.Nil, orderID)
+
+			actual, err := suite.repo.GetOrder(ctx, orderID)
+			require.NoError(t, err)
+
+			assert.False(t, actual.CreatedAt.IsZero())
+
+			order.ID = orderID
+			order.Status = domain.OrderStatusPending
+			assertOrder(t, order, actual)
+		})
+	}
+}
+
+func (suite *orderRepositorySuite) TestGetOrder_NotFound() {
+	t := suite.T()
+
+	_, err := suite.repo.GetOrder(t.Context(), uuid.MustParse(gofakeit.UUID()))
+	require.ErrorIs(t, err, repository.ErrOrderNotFound)
+}
+
+func (suite *orderRepositorySuite) TestListOrders() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+
+	// another owner's order must not be listed
+	_, err := suite.repo.CreateOrder(ctx, domain.Order{OwnerID: gofakeit.UUID()})
+	require.NoError(t, err)
+
+	var orderIDs []uuid.UUID
+	for range 3 {
+		orderID, err := suite.repo.CreateOrder(ctx, domain.Order{
+			OwnerID: ownerID,
+			Items:   []domain.OrderItem{fakeOrderItem()},
+		})
+		require.NoError(t, err)
+
+		// newest first
+		orderIDs = append([]uuid.UUID{orderID}, orderIDs...)
+	}
+
+	firstPage, err := suite.repo.ListOrders(ctx, ownerID, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, firstPage, 2)
+	assert.Equal(t, orderIDs[:2], []uuid.UUID{firstPage[0].ID, firstPage[1].ID})
+
+	for _, o := range firstPage {
+		assert.Equal(t, ownerID, o.OwnerID)
+		assert.Len(t, o.Items, 1)
+	}
+
+	last := firstPage[len(firstPage)-1]
+	cursor := &domain.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+
+	secondPage, err := suite.repo.ListOrders(ctx, ownerID, cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, secondPage, 1)
+	assert.Equal(t, orderIDs[2], secondPage[0].ID)
+
+	emptyPage, err := suite.repo.ListOrders(ctx, gofakeit.UUID(), nil, 2)
+	require.NoError(t, err)
+	assert.Empty(t, emptyPage)
+}
+
+func (suite *orderRepositorySuite) TestUpdateOrderStatus() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+
+	orderID, err := suite.repo.CreateOrder(ctx, domain.Order{OwnerID: ownerID})
+	require.NoError(t, err)
+
+	order, err := suite.repo.GetOrder(ctx, orderID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPending, order.Status)
+
+	paid := domain.OrderStatusChange{From: domain.OrderStatusPending, To: domain.OrderStatusPaid, ChangedBy: "support"}
+
+	updated, err := suite.repo.UpdateOrderStatus(ctx, orderID, paid)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	// stale from status
+	updated, err = suite.repo.UpdateOrderStatus(ctx, orderID, paid)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	updated, err = suite.repo.UpdateOrderStatus(ctx, uuid.MustParse(gofakeit.UUID()), paid)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	order, err = suite.repo.GetOrder(ctx, orderID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+
+	history, err := suite.repo.GetOrderStatusHistory(ctx, orderID)
+	require.NoError(t, err)
+
+	opts := cmpopts.IgnoreFields(domain.OrderStatusChange{}, "ChangedAt")
+	expected := []domain.OrderStatusChange{
+		{To: domain.OrderStatusPending, ChangedBy: ownerID},
+		paid,
+	}
+	assert.Empty(t, cmp.Diff(expected, history, opts))
+
+	for _, change := range history {
+		assert.False(t, change.ChangedAt.IsZero())
+	}
+}
+
+func fakeOrderItem() domain.OrderItem {
+	productID := uuid.MustParse(gofakeit.UUID())
+
+	price := gofakeit.Price(1, 100)
+
+	currencyUnit := repotest.FakeCurrency()
+
+	return domain.OrderItem{
+		ProductID: productID,
+		Price: domain.Money{
+			Amount:   decimal.NewFromFloat(price),
+			Currency: currencyUnit,
+		},
+		Quantity: gofakeit.Number(1, 5),
+	}
+}
+
+func assertOrder(t *testing.T, expected domain.Order, actual domain.Order) {
+	t.Helper()
+
+	// Custom comparer for Money.Currency fields
+	comparer := cmp.Comparer(func(x, y currency.Unit) bool {
+		return x.String() == y.String()
+	})
+
+	// Ignore the CreatedAt fields set by the database and
+	// Treat empty slices as equal to nil
+	opts := cmp.Options{
+		cmpopts.IgnoreFields(domain.Order{}, "CreatedAt"),
+		cmpopts.IgnoreFields(domain.OrderItem{}, "CreatedAt"),
+		cmpopts.SortSlices(func(x, y domain.OrderItem) bool {
+			return x.ProductID.String() < y.ProductID.String()
+		}),
+		cmpopts.EquateEmpty(),
+	}
+
+	diff := cmp.Diff(expected, actual, comparer, opts)
+	assert.Empty(t, diff)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector exports the connection pool statistics, they are read from the pool on every scrape.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquires             *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquires        *prometheus.Desc
+	emptyAcquireWaitTime *prometheus.Desc
+	canceledAcquires     *prometheus.Desc
+}
+
+// NewPoolCollector returns a Prometheus collector of the pool statistics, the metrics are prefixed with db_pool_.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("db_pool_"+name, help, nil, nil)
+	}
+
+	return &poolCollector{
+		pool:                 pool,
+		acquiredConns:        desc("acquired_conns", "Connections currently acquired from the pool."),
+		idleConns:            desc("idle_conns", "Idle connections in the pool."),
+		constructingConns:    desc("constructing_conns", "Connections being established."),
+		totalConns:           desc("total_conns", "All connections in the pool, acquired, idle and constructing."),
+		maxConns:             desc("max_conns", "Maximum size of the pool."),
+		acquires:             desc("acquires_total", "Successful acquires from the pool."),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Time spent acquiring connections."),
+		emptyAcquires:        desc("empty_acquires_total", "Acquires which waited for a connection as the pool was empty."),
+		emptyAcquireWaitTime: desc("empty_acquire_wait_seconds_total", "Time spent waiting for a connection as the pool was empty."),
+		canceledAcquires:     desc("canceled_acquires_total", "Acquires canceled by the context."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	gauge := func(desc *prometheus.Desc, value int32) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value))
+	}
+
+	counter := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value)
+	}
+
+	gauge(c.acquiredConns, stat.AcquiredConns())
+	gauge(c.idleConns, stat.IdleConns())
+	gauge(c.constructingConns, stat.ConstructingConns())
+	gauge(c.totalConns, stat.TotalConns())
+	gauge(c.maxConns, stat.MaxConns())
+
+	counter(c.acquires, float64(stat.AcquireCount()))
+	counter(c.acquireDuration, stat.AcquireDuration().Seconds())
+	counter(c.emptyAcquires, float64(stat.EmptyAcquireCount()))
+	counter(c.emptyAcquireWaitTime, stat.EmptyAcquireWaitTime().Seconds())
+	counter(c.canceledAcquires, float64(stat.CanceledAcquireCount()))
+}
@@ -3,14 +3,65 @@ package repository_test
 import (
 	"context"
 	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
+// postgresSuite is embedded by repository suites, it starts a Postgres container once per suite.
+type postgresSuite struct {
+	suite.Suite
+
+	pool      *pgxpool.Pool
+	repo      repository.Repo
+	container testcontainers.Container
+	connStr   string
+
+	// spans records the query spans of the pool
+	spans *tracetest.SpanRecorder
+}
+
+// before all tests in the suite
+func (suite *postgresSuite) SetupSuite() {
+	ctx := suite.T().Context()
+
+	var err error
+
+	suite.container, suite.connStr, err = startPostgres(ctx)
+	suite.NoError(err)
+
+	poolConfig, err := pgxpool.ParseConfig(suite.connStr)
+	suite.Require().NoError(err)
+
+	suite.spans = tracetest.NewSpanRecorder()
+	poolConfig.ConnConfig.Tracer = repository.NewQueryTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(suite.spans)))
+
+	suite.pool, err = pgxpool.NewWithConfig(ctx, poolConfig)
+	suite.NoError(err)
+
+	suite.repo, err = repository.New(suite.pool)
+	suite.NoError(err)
+}
+
+// after all tests in the suite
+func (suite *postgresSuite) TearDownSuite() {
+	ctx := suite.T().Context()
+
+	if suite.pool != nil {
+		suite.pool.Close()
+	}
+	if suite.container != nil {
+		suite.NoError(suite.container.Terminate(ctx))
+	}
+}
+
 func startPostgres(ctx context.Context) (testcontainers.Container, string, error) {
 	postgresContainer, err := postgres.Run(ctx, "postgres:17.4-alpine",
 		postgres.BasicWaitStrategies(),
-		postgres.WithInitScripts("migrations/01_cart_items.up.sql"), // TODO: fix
 	)
 	if err != nil {
 		return nil, "", fmt.Errorf("postgres.Run: %w", err)
@@ -21,5 +72,11 @@ func startPostgres(ctx context.Context) (testcontainers.Container, string, error
 		return nil, "", fmt.Errorf("pc.ConnectionString: %w", err)
 	}
 
+	// the same migrations as the service runs on startup,
+	// the container is returned to be terminated by the caller
+	if err := repository.MigrateUp(connStr); err != nil {
+		return postgresContainer, "", fmt.Errorf("repository.MigrateUp: %w", err)
+	}
+
 	return postgresContainer, connStr, nil
 }
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"golang.org/x/text/currency"
+)
+
+func (r *repo) GetProduct(ctx context.Context, productID uuid.UUID) (domain.Product, error) {
+	ctx = withStatement(ctx, "GetProduct")
+
+	var (
+		p           domain.Product
+		currencyStr string
+	)
+
+	err := r.db.QueryRow(ctx, `
+			SELECT id, name, price_amount, price_currency, updated_at 
+			FROM products 
+			WHERE id = $1`,
+		productID).
+		Scan(&p.ID, &p.Name, &p.Price.Amount, &currencyStr, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return p, ErrProductNotFound
+		}
+		return p, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	currencyUnit, err := currency.ParseISO(currencyStr)
+	if err != nil {
+		return p, fmt.Errorf("currency.ParseISO[%s]: %w", currencyStr, err)
+	}
+
+	p.Price.Currency = currencyUnit
+
+	return p, nil
+}
+
+func (r *repo) GetProducts(ctx context.Context, productIDs []uuid.UUID) ([]domain.Product, error) {
+	ctx = withStatement(ctx, "GetProducts")
+
+	rows, err := r.db.Query(ctx, `
+			SELECT id, name, price_amount, price_currency, updated_at 
+			FROM products 
+			WHERE id = ANY($1)`,
+		productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+
+	products, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Product, error) {
+		var (
+			p           domain.Product
+			currencyStr string
+		)
+
+		if err := row.Scan(&p.ID, &p.Name, &p.Price.Amount, &currencyStr, &p.UpdatedAt); err != nil {
+			return domain.Product{}, fmt.Errorf("row.Scan: %w", err)
+		}
+
+		currencyUnit, err := currency.ParseISO(currencyStr)
+		if err != nil {
+			return domain.Product{}, fmt.Errorf("currency.ParseISO[%s]: %w", currencyStr, err)
+		}
+
+		p.Price.Currency = currencyUnit
+
+		return p, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
+	}
+
+	return products, nil
+}
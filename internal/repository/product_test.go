@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/nikolayk812/go-tests/internal/repository/repotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+	"testing"
+)
+
+type productRepositorySuite struct {
+	postgresSuite
+}
+
+// entry point to run the tests in the suite
+func TestProductRepositorySuite(t *testing.T) {
+	// Verifies no leaks after all tests in the suite run.
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(productRepositorySuite))
+}
+
+func (suite *productRepositorySuite) TestGetProduct() {
+	t := suite.T()
+	ctx := t.Context()
+
+	item := repotest.FakeCartItem()
+	name := gofakeit.Word()
+
+	_, err := suite.pool.Exec(ctx, `
+			INSERT INTO products (id, name, price_amount, price_currency) 
+			VALUES ($1, $2, $3, $4)`,
+		item.ProductID, name, item.Price.Amount, item.Price.Currency)
+	require.NoError(t, err)
+
+	product, err := suite.repo.GetProduct(ctx, item.ProductID)
+	require.NoError(t, err)
+
+	assert.Equal(t, item.ProductID, product.ID)
+	assert.Equal(t, name, product.Name)
+	assert.True(t, item.Price.Equal(product.Price), "want %s, got %s", item.Price, product.Price)
+	assert.False(t, product.UpdatedAt.IsZero())
+
+	_, err = suite.repo.GetProduct(ctx, uuid.MustParse(gofakeit.UUID()))
+	require.ErrorIs(t, err, repository.ErrProductNotFound)
+}
+
+func (suite *productRepositorySuite) TestGetProducts() {
+	t := suite.T()
+	ctx := t.Context()
+
+	item1 := repotest.FakeCartItem()
+	item2 := repotest.FakeCartItem()
+
+	for _, item := range []domain.CartItem{item1, item2} {
+		_, err := suite.pool.Exec(ctx, `
+			INSERT INTO products (id, name, price_amount, price_currency) 
+			VALUES ($1, $2, $3, $4)`,
+			item.ProductID, gofakeit.Word(), item.Price.Amount, item.Price.Currency)
+		require.NoError(t, err)
+	}
+
+	unknownID := uuid.MustParse(gofakeit.UUID())
+
+	products, err := suite.repo.GetProducts(ctx, []uuid.UUID{item1.ProductID, unknownID, item2.ProductID})
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+
+	actualIDs := []uuid.UUID{products[0].ID, products[1].ID}
+	assert.ElementsMatch(t, []uuid.UUID{item1.ProductID, item2.ProductID}, actualIDs)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/tracelog"
+	"github.com/nikolayk812/go-tests/internal/logger"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// logQuery logs queries with the logger of the query context, so that they carry the request ID.
+// Queries are logged at debug level, failed queries at warn level as the error is returned to the caller anyway.
+func logQuery(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+	slogLevel := slog.LevelDebug
+	if level <= tracelog.LogLevelError {
+		slogLevel = slog.LevelWarn
+	}
+
+	log := logger.From(ctx)
+	if !log.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(data))
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		attrs = append(attrs, slog.Any(key, data[key]))
+	}
+
+	log.LogAttrs(ctx, slogLevel, msg, attrs...)
+}
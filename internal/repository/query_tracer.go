@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/tracelog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+const tracerName = "github.com/nikolayk812/go-tests/internal/repository"
+
+// queryTracer logs every query and records a span for it, the span is named after the statement,
+// see withStatement, and carries the number of affected rows.
+type queryTracer struct {
+	log    *tracelog.TraceLog
+	tracer trace.Tracer
+}
+
+// NewQueryTracer returns the tracer of the pool queries, the spans are created by the tracer provider.
+func NewQueryTracer(tp trace.TracerProvider) pgx.QueryTracer {
+	return &queryTracer{
+		log: &tracelog.TraceLog{
+			Logger:   tracelog.LoggerFunc(logQuery),
+			LogLevel: tracelog.LogLevelInfo,
+		},
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx = t.log.TraceQueryStart(ctx, conn, data)
+
+	operation := sqlOperation(data.SQL)
+
+	name := statementFrom(ctx)
+	if name == "" {
+		name = operation
+	}
+
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(data.SQL),
+			attribute.String("db.statement.name", name),
+		))
+
+	return ctx
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+
+	span.End()
+
+	t.log.TraceQueryEnd(ctx, conn, data)
+}
+
+type statementKey struct{}
+
+// withStatement names the queries run with ctx, repository methods name their queries after themselves,
+// so that the query spans tell which method ran them. Helpers, e.g. getOrderItems, keep the name of the caller.
+func withStatement(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, statementKey{}, name)
+}
+
+func statementFrom(ctx context.Context) string {
+	name, _ := ctx.Value(statementKey{}).(string)
+	return name
+}
+
+// sqlOperation returns the first keyword of the query, e.g. SELECT.
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(fields[0])
+}
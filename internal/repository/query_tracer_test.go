@@ -0,0 +1,72 @@
+package repository_test
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestQueryTracer(t *testing.T) {
+	const sql = "DELETE FROM cart_items WHERE owner_id = $1 AND product_id = $2"
+
+	tests := []struct {
+		name       string
+		end        pgx.TraceQueryEndData
+		wantRows   int64
+		wantStatus codes.Code
+	}{
+		{
+			name:     "success",
+			end:      pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("DELETE 1")},
+			wantRows: 1,
+		},
+		{
+			name:       "error",
+			end:        pgx.TraceQueryEndData{Err: errors.New("connection refused")},
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			tracer := repository.NewQueryTracer(tp)
+
+			// the queries are traced without a database, the zero connection has no PID
+			conn := &pgx.Conn{}
+
+			ctx := tracer.TraceQueryStart(t.Context(), conn, pgx.TraceQueryStartData{SQL: sql})
+			tracer.TraceQueryEnd(ctx, conn, tt.end)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+			assert.Equal(t, "DELETE", span.Name())
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+			assert.Equal(t, tt.wantStatus, span.Status().Code)
+
+			attrs := attribute.NewSet(span.Attributes()...)
+
+			rows, _ := attrs.Value("db.rows_affected")
+			assert.Equal(t, tt.wantRows, rows.AsInt64())
+
+			query, _ := attrs.Value("db.query.text")
+			assert.Equal(t, sql, query.AsString())
+
+			system, _ := attrs.Value("db.system")
+			assert.Equal(t, "postgresql", system.AsString())
+		})
+	}
+}
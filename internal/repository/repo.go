@@ -1,26 +1,147 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nikolayk812/go-tests/internal/logger"
 	"github.com/nikolayk812/go-tests/internal/port"
+	"time"
 )
 
 type Repo interface {
 	port.CartRepository
 	port.OrderRepository
+	port.UnitOfWork
+	port.ExchangeRateProvider
+	port.ProductCatalog
+	port.IdempotencyStore
 }
 
+// dbtx is implemented by both *pgxpool.Pool and pgx.Tx,
+// so the same queries run either on the pool or inside a transaction.
+type dbtx interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+const (
+	defaultTxRetries    = 3
+	defaultTxRetryDelay = 10 * time.Millisecond
+)
+
 type repo struct {
-	pool *pgxpool.Pool
+	db dbtx
+
+	txOptions    pgx.TxOptions
+	txRetries    int
+	txRetryDelay time.Duration
+
+	// inTx is set on repositories bound to a transaction
+	inTx bool
+}
+
+// Option configures the repository created by New.
+type Option func(r *repo)
+
+// WithIsolationLevel sets the isolation level of the transactions started by WithTx,
+// the database default is used if not set.
+func WithIsolationLevel(level pgx.TxIsoLevel) Option {
+	return func(r *repo) {
+		r.txOptions.IsoLevel = level
+	}
 }
 
-func New(pool *pgxpool.Pool) (Repo, error) {
+// WithTxRetries sets how many times WithTx retries a transaction failed with a serialization failure,
+// the delay doubles after every retry.
+func WithTxRetries(retries int, delay time.Duration) Option {
+	return func(r *repo) {
+		r.txRetries = retries
+		r.txRetryDelay = delay
+	}
+}
+
+func New(pool *pgxpool.Pool, opts ...Option) (Repo, error) {
 	if pool == nil {
 		return nil, errors.New("pool is nil")
 	}
 
-	return &repo{
-		pool: pool,
-	}, nil
+	r := &repo{
+		db:           pool,
+		txRetries:    defaultTxRetries,
+		txRetryDelay: defaultTxRetryDelay,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.txRetries < 0 {
+		return nil, errors.New("tx retries is negative")
+	}
+
+	return r, nil
+}
+
+// WithTx runs fn in a transaction, the error returned by fn is passed through as is.
+// The transaction is retried if it fails with a serialization failure, so fn must be safe to run again.
+// Calling WithTx on a repository already bound to a transaction creates a savepoint,
+// retrying is then left to the outermost transaction.
+func (r *repo) WithTx(ctx context.Context, fn func(repo port.Repository) error) error {
+	if r.inTx {
+		return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+			return fn(r.bind(tx))
+		})
+	}
+
+	pool, ok := r.db.(txBeginner)
+	if !ok {
+		return fmt.Errorf("unexpected db type %T", r.db)
+	}
+
+	delay := r.txRetryDelay
+
+	for attempt := 0; ; attempt++ {
+		err := pgx.BeginTxFunc(ctx, pool, r.txOptions, func(tx pgx.Tx) error {
+			return fn(r.bind(tx))
+		})
+		if err == nil || attempt >= r.txRetries || !isSerializationFailure(err) {
+			return err
+		}
+
+		logger.From(ctx).Warn("retrying transaction after serialization failure",
+			"attempt", attempt+1, "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// bind returns a copy of the repository which runs queries on the transaction.
+func (r *repo) bind(tx pgx.Tx) *repo {
+	txRepo := *r
+	txRepo.db = tx
+	txRepo.inTx = true
+
+	return &txRepo
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.SerializationFailure
 }
@@ -0,0 +1,242 @@
+package repository_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/brianvoe/gofakeit"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/nikolayk812/go-tests/internal/repository/repotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+	"testing"
+	"time"
+)
+
+type txRepositorySuite struct {
+	postgresSuite
+}
+
+// entry point to run the tests in the suite
+func TestTxRepositorySuite(t *testing.T) {
+	// Verifies no leaks after all tests in the suite run.
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(txRepositorySuite))
+}
+
+func (suite *txRepositorySuite) TestWithTx() {
+	item := repotest.FakeCartItem()
+	errRollback := errors.New("rollback")
+
+	testCases := []struct {
+		name      string
+		fn        func(ownerID string) func(repo port.Repository) error
+		wantItems []domain.CartItem
+		wantError error
+	}{
+		{
+			name: "commit",
+			fn: func(ownerID string) func(repo port.Repository) error {
+				return func(repo port.Repository) error {
+					return repo.AddItem(suite.T().Context(), ownerID, item)
+				}
+			},
+			wantItems: []domain.CartItem{item},
+		},
+		{
+			name: "rollback",
+			fn: func(ownerID string) func(repo port.Repository) error {
+				return func(repo port.Repository) error {
+					if err := repo.AddItem(suite.T().Context(), ownerID, item); err != nil {
+						return err
+					}
+					return errRollback
+				}
+			},
+			wantError: errRollback,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			t := suite.T()
+			ctx := t.Context()
+
+			ownerID := gofakeit.UUID()
+
+			err := suite.repo.WithTx(ctx, tc.fn(ownerID))
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			cart, err := suite.repo.GetCart(ctx, ownerID)
+			require.NoError(t, err)
+
+			repotest.AssertCart(t, domain.Cart{OwnerID: ownerID, Items: tc.wantItems}, cart)
+		})
+	}
+}
+
+func (suite *txRepositorySuite) TestWithTx_Retry() {
+	serializationFailure := &pgconn.PgError{Code: pgerrcode.SerializationFailure}
+
+	testCases := []struct {
+		name         string
+		failures     int
+		err          error
+		wantAttempts int
+		wantError    bool
+	}{
+		{
+			name:         "retried until success",
+			failures:     2,
+			err:          serializationFailure,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries exhausted",
+			failures:     5,
+			err:          serializationFailure,
+			wantAttempts: 3,
+			wantError:    true,
+		},
+		{
+			name:         "other errors are not retried",
+			failures:     1,
+			err:          errors.New("unexpected error"),
+			wantAttempts: 1,
+			wantError:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			t := suite.T()
+			ctx := t.Context()
+
+			repo, err := repository.New(suite.pool,
+				repository.WithIsolationLevel(pgx.Serializable),
+				repository.WithTxRetries(2, time.Millisecond))
+			require.NoError(t, err)
+
+			var attempts int
+
+			err = repo.WithTx(ctx, func(repo port.Repository) error {
+				attempts++
+				if attempts <= tc.failures {
+					return tc.err
+				}
+				return nil
+			})
+			if tc.wantError {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.wantAttempts, attempts)
+		})
+	}
+}
+
+func (suite *txRepositorySuite) TestWithTx_Nested() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	item1 := repotest.FakeCartItem()
+	item2 := repotest.FakeCartItem()
+
+	errRollback := errors.New("rollback")
+
+	err := suite.repo.WithTx(ctx, func(repo port.Repository) error {
+		if err := repo.AddItem(ctx, ownerID, item1); err != nil {
+			return err
+		}
+
+		// only the savepoint is rolled back
+		err := repo.(port.UnitOfWork).WithTx(ctx, func(repo port.Repository) error {
+			if err := repo.AddItem(ctx, ownerID, item2); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			return err
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	repotest.AssertCart(t, domain.Cart{OwnerID: ownerID, Items: []domain.CartItem{item1}}, cart)
+}
+
+func (suite *txRepositorySuite) TestLockCart_ConcurrentAdd() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	item1 := repotest.FakeCartItem()
+	item2 := repotest.FakeCartItem()
+
+	require.NoError(t, suite.repo.AddItem(ctx, ownerID, item1))
+
+	added := make(chan error, 1)
+
+	// a checkout: the cart is locked before its items are read and removed
+	err := suite.repo.WithTx(ctx, func(repo port.Repository) error {
+		if _, err := repo.LockCart(ctx, ownerID); err != nil {
+			return err
+		}
+
+		go func() {
+			added <- suite.repo.WithTx(ctx, func(repo port.Repository) error {
+				if _, err := repo.LockCart(ctx, ownerID); err != nil {
+					return err
+				}
+				return repo.AddItem(ctx, ownerID, item2)
+			})
+		}()
+
+		cart, err := repo.GetCart(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+
+		// the concurrent add waits for the lock
+		select {
+		case err := <-added:
+			return fmt.Errorf("item added while the cart is locked: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		for _, item := range cart.Items {
+			if _, err := repo.DeleteItem(ctx, ownerID, item.ProductID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, <-added)
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	// the item added during the checkout stays in the cart
+	repotest.AssertCart(t, domain.Cart{OwnerID: ownerID, Items: []domain.CartItem{item2}}, cart)
+}
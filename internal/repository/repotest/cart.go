@@ -0,0 +1,492 @@
+package repotest
+
+import (
+	"errors"
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/currency"
+	"testing"
+	"time"
+)
+
+// CartRepositorySuite is the conformance suite every port.CartRepository implementation must pass,
+// so that the implementations can not drift apart.
+// Every test uses its own owner, so the repository can be shared with other tests.
+type CartRepositorySuite struct {
+	suite.Suite
+
+	repo port.CartRepository
+}
+
+// RunCartRepositorySuite runs the conformance suite against the repository.
+func RunCartRepositorySuite(t *testing.T, repo port.CartRepository) {
+	t.Helper()
+
+	suite.Run(t, &CartRepositorySuite{repo: repo})
+}
+
+func (suite *CartRepositorySuite) TestGetCart() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	assert.Equal(t, ownerID, cart.OwnerID)
+	assert.Empty(t, cart.Items)
+
+	item := FakeCartItem()
+	before := time.Now()
+
+	err = suite.repo.AddItem(ctx, ownerID, item)
+	require.NoError(t, err)
+
+	cart, err = suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+
+	// CreatedAt is assigned by the repository
+	createdAt := cart.Items[0].CreatedAt
+	assert.WithinDuration(t, before, createdAt, time.Minute)
+
+	// other owners carts are not visible
+	otherCart, err := suite.repo.GetCart(ctx, gofakeit.UUID())
+	require.NoError(t, err)
+	assert.Empty(t, otherCart.Items)
+}
+
+func (suite *CartRepositorySuite) TestAddItem() {
+	item1 := FakeCartItem()
+	item2 := FakeCartItem()
+
+	item1Twice := item1
+	item1Twice.Quantity = 2 * item1.Quantity
+
+	item1OtherPrice := item1
+	item1OtherPrice.Price.Amount = item1.Price.Amount.Add(decimal.NewFromInt(1))
+
+	testCases := []struct {
+		name      string
+		items     []domain.CartItem
+		wantItems []domain.CartItem
+		wantError error
+	}{
+		{
+			name: "empty cart: ok",
+		},
+		{
+			name:      "single item: ok",
+			items:     []domain.CartItem{item1},
+			wantItems: []domain.CartItem{item1},
+		},
+		{
+			name:      "three items: ok",
+			items:     []domain.CartItem{item1, item2},
+			wantItems: []domain.CartItem{item1, item2},
+		},
+		{
+			name:      "same item twice: quantity incremented",
+			items:     []domain.CartItem{item1, item1},
+			wantItems: []domain.CartItem{item1Twice},
+		},
+		{
+			name:      "same item with other price: fail",
+			items:     []domain.CartItem{item1, item1OtherPrice},
+			wantError: repository.ErrCartDuplicateItem,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			t := suite.T()
+			ctx := t.Context()
+
+			ownerID := gofakeit.UUID()
+
+			for _, item := range tc.items {
+				err := suite.repo.AddItem(ctx, ownerID, item)
+				if err != nil {
+					require.ErrorIs(t, err, tc.wantError)
+					return
+				}
+			}
+
+			require.NoError(t, tc.wantError)
+
+			cart, err := suite.repo.GetCart(ctx, ownerID)
+			require.NoError(t, err)
+
+			expectedCart := domain.Cart{
+				OwnerID: ownerID,
+				Items:   tc.wantItems,
+			}
+			AssertCart(t, expectedCart, cart)
+		})
+	}
+}
+
+func (suite *CartRepositorySuite) TestDeleteItem() {
+	item1 := FakeCartItem()
+	item2 := FakeCartItem()
+
+	testCases := []struct {
+		name     string
+		items    []domain.CartItem
+		deleteID uuid.UUID
+		deleted  bool
+	}{
+		{
+			name:     "empty cart",
+			deleteID: item1.ProductID,
+		},
+		{
+			name:     "existing item",
+			items:    []domain.CartItem{item1},
+			deleteID: item1.ProductID,
+			deleted:  true,
+		},
+		{
+			name:     "one of multiple items",
+			items:    []domain.CartItem{item1, item2},
+			deleteID: item1.ProductID,
+			deleted:  true,
+		},
+		{
+			name:     "non-existent item",
+			items:    []domain.CartItem{item1},
+			deleteID: uuid.MustParse(gofakeit.UUID()),
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			t := suite.T()
+			ctx := t.Context()
+
+			ownerID := gofakeit.UUID()
+
+			for _, item := range tc.items {
+				err := suite.repo.AddItem(ctx, ownerID, item)
+				require.NoError(t, err)
+			}
+
+			deleted, err := suite.repo.DeleteItem(ctx, ownerID, tc.deleteID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.deleted, deleted)
+
+			cart, err := suite.repo.GetCart(ctx, ownerID)
+			require.NoError(t, err)
+
+			expectedItems := make([]domain.CartItem, 0)
+			for _, item := range tc.items {
+				if item.ProductID != tc.deleteID {
+					expectedItems = append(expectedItems, item)
+				}
+			}
+
+			expectedCart := domain.Cart{
+				OwnerID: ownerID,
+				Items:   expectedItems,
+			}
+			AssertCart(t, expectedCart, cart)
+		})
+	}
+}
+
+func (suite *CartRepositorySuite) TestDeleteItems() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	item1 := FakeCartItem()
+	item2 := FakeCartItem()
+	item3 := FakeCartItem()
+
+	for _, item := range []domain.CartItem{item1, item2, item3} {
+		err := suite.repo.AddItem(ctx, ownerID, item)
+		require.NoError(t, err)
+	}
+
+	unknownID := uuid.MustParse(gofakeit.UUID())
+
+	deleted, err := suite.repo.DeleteItems(ctx, ownerID, []uuid.UUID{item1.ProductID, item3.ProductID, unknownID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+	AssertCart(t, domain.Cart{OwnerID: ownerID, Items: []domain.CartItem{item2}}, cart)
+
+	deleted, err = suite.repo.DeleteItems(ctx, ownerID, []uuid.UUID{item1.ProductID})
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
+
+func (suite *CartRepositorySuite) TestClearCart() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	otherOwnerID := gofakeit.UUID()
+	item1 := FakeCartItem()
+	item2 := FakeCartItem()
+
+	for _, item := range []domain.CartItem{item1, item2} {
+		err := suite.repo.AddItem(ctx, ownerID, item)
+		require.NoError(t, err)
+	}
+
+	err := suite.repo.AddItem(ctx, otherOwnerID, item1)
+	require.NoError(t, err)
+
+	err = suite.repo.ClearCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+	assert.Empty(t, cart.Items)
+
+	// other carts are not affected
+	otherCart, err := suite.repo.GetCart(ctx, otherOwnerID)
+	require.NoError(t, err)
+	AssertCart(t, domain.Cart{OwnerID: otherOwnerID, Items: []domain.CartItem{item1}}, otherCart)
+
+	// clearing an empty cart is not an error
+	err = suite.repo.ClearCart(ctx, ownerID)
+	require.NoError(t, err)
+}
+
+func (suite *CartRepositorySuite) TestUpdateItemQuantity() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	item := FakeCartItem()
+
+	updated, err := suite.repo.UpdateItemQuantity(ctx, ownerID, item.ProductID, 3)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	err = suite.repo.AddItem(ctx, ownerID, item)
+	require.NoError(t, err)
+
+	updated, err = suite.repo.UpdateItemQuantity(ctx, ownerID, item.ProductID, 3)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	item.Quantity = 3
+	AssertCart(t, domain.Cart{OwnerID: ownerID, Items: []domain.CartItem{item}}, cart)
+}
+
+// TestNonPositiveQuantity checks the items keep a positive quantity, a zero quantity is not a removal.
+func (suite *CartRepositorySuite) TestNonPositiveQuantity() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	item := FakeCartItem()
+
+	err := suite.repo.AddItem(ctx, ownerID, item)
+	require.NoError(t, err)
+
+	for _, quantity := range []int{0, -1} {
+		other := FakeCartItem()
+		other.Quantity = quantity
+		require.Error(t, suite.repo.AddItem(ctx, ownerID, other), "new item, quantity %d", quantity)
+
+		existing := item
+		existing.Quantity = quantity
+		require.Error(t, suite.repo.AddItem(ctx, ownerID, existing), "existing item, quantity %d", quantity)
+
+		_, err = suite.repo.UpdateItemQuantity(ctx, ownerID, item.ProductID, quantity)
+		require.Error(t, err, "update, quantity %d", quantity)
+	}
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	AssertCart(t, domain.Cart{OwnerID: ownerID, Items: []domain.CartItem{item}}, cart)
+}
+
+func (suite *CartRepositorySuite) TestUpdateItemPrice() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	item := FakeCartItem()
+
+	newPrice := item.Price
+	newPrice.Amount = item.Price.Amount.Add(decimal.NewFromInt(1))
+
+	updated, err := suite.repo.UpdateItemPrice(ctx, ownerID, item.ProductID, newPrice)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	err = suite.repo.AddItem(ctx, ownerID, item)
+	require.NoError(t, err)
+
+	updated, err = suite.repo.UpdateItemPrice(ctx, ownerID, item.ProductID, newPrice)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	item.Price = newPrice
+	AssertCart(t, domain.Cart{OwnerID: ownerID, Items: []domain.CartItem{item}}, cart)
+}
+
+func (suite *CartRepositorySuite) TestVersion() {
+	t := suite.T()
+	ctx := t.Context()
+
+	ownerID := gofakeit.UUID()
+	item1 := FakeCartItem()
+	item2 := FakeCartItem()
+
+	otherPrice := item1.Price
+	otherPrice.Amount = item1.Price.Amount.Add(decimal.NewFromInt(1))
+
+	item1OtherPrice := item1
+	item1OtherPrice.Price = otherPrice
+
+	// a cart which never had items
+	version := suite.assertVersion(ownerID, 0, false)
+
+	changes := []struct {
+		name    string
+		change  func() error
+		changed bool
+	}{
+		{"add item", func() error { return suite.repo.AddItem(ctx, ownerID, item1) }, true},
+		{"add same item", func() error { return suite.repo.AddItem(ctx, ownerID, item1) }, true},
+		{"add item with other price", func() error {
+			err := suite.repo.AddItem(ctx, ownerID, item1OtherPrice)
+			if errors.Is(err, repository.ErrCartDuplicateItem) {
+				return nil
+			}
+			return err
+		}, false},
+		{"add other item", func() error { return suite.repo.AddItem(ctx, ownerID, item2) }, true},
+		{"update quantity", func() error {
+			_, err := suite.repo.UpdateItemQuantity(ctx, ownerID, item1.ProductID, 7)
+			return err
+		}, true},
+		{"update price", func() error {
+			_, err := suite.repo.UpdateItemPrice(ctx, ownerID, item1.ProductID, otherPrice)
+			return err
+		}, true},
+		{"delete missing item", func() error {
+			_, err := suite.repo.DeleteItem(ctx, ownerID, uuid.MustParse(gofakeit.UUID()))
+			return err
+		}, false},
+		{"delete item", func() error {
+			_, err := suite.repo.DeleteItem(ctx, ownerID, item2.ProductID)
+			return err
+		}, true},
+		{"delete items", func() error {
+			_, err := suite.repo.DeleteItems(ctx, ownerID, []uuid.UUID{item1.ProductID})
+			return err
+		}, true},
+		{"clear empty cart", func() error { return suite.repo.ClearCart(ctx, ownerID) }, false},
+		{"add item again", func() error { return suite.repo.AddItem(ctx, ownerID, item2) }, true},
+		{"clear cart", func() error { return suite.repo.ClearCart(ctx, ownerID) }, true},
+	}
+
+	for _, c := range changes {
+		require.NoError(t, c.change(), c.name)
+
+		version = suite.assertVersion(ownerID, version, c.changed)
+	}
+
+	// other carts have their own versions
+	suite.assertVersion(gofakeit.UUID(), 0, false)
+}
+
+// assertVersion asserts the cart version increased if the cart changed, or stayed the same otherwise,
+// and that LockCart returns the same version as GetCart. It returns the current version.
+func (suite *CartRepositorySuite) assertVersion(ownerID string, previous int64, changed bool) int64 {
+	t := suite.T()
+	ctx := t.Context()
+
+	cart, err := suite.repo.GetCart(ctx, ownerID)
+	require.NoError(t, err)
+
+	if changed {
+		assert.Greater(t, cart.Version, previous)
+	} else {
+		assert.Equal(t, previous, cart.Version)
+	}
+
+	version, err := suite.repo.LockCart(ctx, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, cart.Version, version)
+
+	return cart.Version
+}
+
+// FakeCartItem returns a random cart item without CreatedAt.
+func FakeCartItem() domain.CartItem {
+	productID := uuid.MustParse(gofakeit.UUID())
+
+	price := gofakeit.Price(1, 100)
+
+	currencyUnit := FakeCurrency()
+
+	return domain.CartItem{
+		ProductID: productID,
+		Price: domain.Money{
+			Amount:   decimal.NewFromFloat(price),
+			Currency: currencyUnit,
+		},
+		Quantity: gofakeit.Number(1, 5),
+	}
+}
+
+// AssertCart compares carts ignoring the item order, CreatedAt and the version, see TestVersion.
+func AssertCart(t *testing.T, expected domain.Cart, actual domain.Cart) {
+	t.Helper()
+
+	// Custom comparer for Money.Currency fields
+	comparer := cmp.Comparer(func(x, y currency.Unit) bool {
+		return x.String() == y.String()
+	})
+
+	// Ignore the CreatedAt field in CartItem,
+	// Ignore the order of items and
+	// Treat empty slices as equal to nil
+	opts := cmp.Options{
+		cmpopts.IgnoreFields(domain.Cart{}, "Version"),
+		cmpopts.IgnoreFields(domain.CartItem{}, "CreatedAt"),
+		cmpopts.SortSlices(func(x, y domain.CartItem) bool {
+			return x.ProductID.String() < y.ProductID.String()
+		}),
+		cmpopts.EquateEmpty(),
+	}
+
+	diff := cmp.Diff(expected, actual, comparer, opts)
+	assert.Empty(t, diff)
+}
+
+// FakeCurrency skips codes gofakeit knows but x/text does not recognize, e.g. GGP
+func FakeCurrency() currency.Unit {
+	for {
+		if unit, err := currency.ParseISO(gofakeit.CurrencyShort()); err == nil {
+			return unit
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/logger"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// AccessLog logs every request once it is handled. The request context carries a logger with the request ID,
+// so that the service and repository logs of the request can be correlated with the access log,
+// and with the trace ID of a traced request.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		log := slog.Default().With("request_id", requestIDFrom(c))
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			log = log.With("trace_id", spanContext.TraceID().String())
+		}
+		c.Request = c.Request.WithContext(logger.With(c.Request.Context(), log))
+
+		c.Next()
+
+		status := c.Writer.Status()
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			// the route template rather than the path, e.g. /carts/:owner_id
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+		}
+
+		if ownerID := c.Param("owner_id"); ownerID != "" {
+			attrs = append(attrs, slog.String("owner_id", ownerID))
+		}
+
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.Last().Error()))
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		log.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// errPanic is rendered as the internal error problem, the panic itself is only logged.
+var errPanic = errors.New("handler panicked")
+
+// Recovery logs a panic of a handler with the request logger and responds with the internal error problem.
+// The panic skips ErrorHandler, so the problem is rendered here.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logger.From(c.Request.Context()).Error("panic recovered",
+			"panic", recovered,
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"stack", string(debug.Stack()))
+
+		c.Abort()
+
+		// the handler may have written the response before it panicked
+		if c.Writer.Written() {
+			return
+		}
+
+		renderProblem(c, errPanic)
+	})
+}
@@ -0,0 +1,184 @@
+package rest_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/logger"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		mockSetup  func(s *service.MockCartService)
+		wantStatus int
+		wantLevel  string
+		wantError  string
+	}{
+		{
+			name: "success",
+			mockSetup: func(s *service.MockCartService) {
+				s.On("GetCart", mock.Anything, "owner1").
+					Run(func(args mock.Arguments) {
+						logger.From(args.Get(0).(context.Context)).Info("in service")
+					}).
+					Return(domain.Cart{OwnerID: "owner1"}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantLevel:  "INFO",
+		},
+		{
+			name: "unexpected error",
+			mockSetup: func(s *service.MockCartService) {
+				s.On("GetCart", mock.Anything, "owner1").
+					Run(func(args mock.Arguments) {
+						logger.From(args.Get(0).(context.Context)).Info("in service")
+					}).
+					Return(domain.Cart{}, errors.New("db.Query: connection refused"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantLevel:  "ERROR",
+			wantError:  "db.Query: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+
+			mockService := new(service.MockCartService)
+			tt.mockSetup(mockService)
+
+			router := newRouter(t, mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/carts/owner1", nil)
+			req.Header.Set(rest.RequestIDHeader, "request-1")
+			req.Header.Set("Authorization", bearer(t, "owner1"))
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			entries := logEntries(t, logs)
+			require.Len(t, entries, 2)
+
+			// the service log carries the request attributes
+			serviceEntry := entries[0]
+			assert.Equal(t, "in service", serviceEntry["msg"])
+			assert.Equal(t, "request-1", serviceEntry["request_id"])
+			assert.Equal(t, "owner1", serviceEntry["subject"])
+
+			accessEntry := entries[1]
+			assert.Equal(t, "request", accessEntry["msg"])
+			assert.Equal(t, tt.wantLevel, accessEntry["level"])
+			assert.Equal(t, "request-1", accessEntry["request_id"])
+			assert.Equal(t, http.MethodGet, accessEntry["method"])
+			assert.Equal(t, "/carts/:owner_id", accessEntry["route"])
+			assert.Equal(t, float64(tt.wantStatus), accessEntry["status"])
+			assert.Equal(t, float64(w.Body.Len()), accessEntry["bytes"])
+			assert.Equal(t, "owner1", accessEntry["owner_id"])
+			assert.Contains(t, accessEntry, "latency")
+
+			if tt.wantError != "" {
+				assert.Equal(t, tt.wantError, accessEntry["error"])
+			} else {
+				assert.NotContains(t, accessEntry, "error")
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logs := captureLogs(t)
+
+	mockService := new(service.MockCartService)
+	mockService.On("GetCart", mock.Anything, "owner1").Panic("boom")
+
+	router := newRouter(t, mockService)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/carts/owner1", nil)
+	req.Header.Set(rest.RequestIDHeader, "request-1")
+	req.Header.Set("Authorization", bearer(t, "owner1"))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, dto.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem dto.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, dto.ProblemTypeInternal, problem.Type)
+	assert.Equal(t, "request-1", problem.RequestID)
+
+	entries := logEntries(t, logs)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "panic recovered", entries[0]["msg"])
+	assert.Equal(t, "boom", entries[0]["panic"])
+	assert.Equal(t, "request-1", entries[0]["request_id"])
+	assert.NotEmpty(t, entries[0]["stack"])
+
+	assert.Equal(t, "request", entries[1]["msg"])
+	assert.Equal(t, float64(http.StatusInternalServerError), entries[1]["status"])
+}
+
+func newRouter(t *testing.T, cartService service.CartService) *gin.Engine {
+	t.Helper()
+
+	cartHandler, err := rest.NewCart(cartService)
+	require.NoError(t, err)
+
+	orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+	require.NoError(t, err)
+
+	return rest.SetupRouter(cartHandler, orderHandler, newVerifier(t))
+}
+
+// captureLogs sets a JSON default logger writing into the returned buffer for the duration of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	return &buf
+}
+
+func logEntries(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/auth"
+	"github.com/nikolayk812/go-tests/internal/logger"
+	"strings"
+)
+
+var (
+	errUnauthenticated = errors.New("missing or invalid bearer token")
+	errForbidden       = errors.New("access to another owner is forbidden")
+	errAdminRequired   = errors.New("admin scope required")
+)
+
+// Authenticate verifies the bearer token and puts its principal into the request context.
+func Authenticate(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			_ = c.Error(errUnauthenticated)
+			c.Abort()
+			return
+		}
+
+		principal, err := verifier.Verify(token)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			_ = c.Error(errors.Join(errUnauthenticated, err))
+			c.Abort()
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		// the service and repository logs of the request tell who made it
+		ctx = logger.With(ctx, logger.From(ctx).With("subject", principal.Subject))
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequireOwner rejects access to the :owner_id of the route unless it is the subject of the token
+// or the token has the admin scope.
+func RequireOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !principalFrom(c).CanAccess(c.Param("owner_id")) {
+			_ = c.Error(errForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAdmin rejects the tokens without the admin scope, e.g. customers must not change the status of their orders.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !principalFrom(c).Admin {
+			_ = c.Error(errAdminRequired)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Me sets the :owner_id param to the subject of the token, so that the /me routes share the owner handlers.
+func Me() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Params = append(c.Params, gin.Param{Key: "owner_id", Value: principalFrom(c).Subject})
+
+		c.Next()
+	}
+}
+
+// principalFrom returns the principal set by Authenticate, it is zero for unauthenticated routes.
+func principalFrom(c *gin.Context) auth.Principal {
+	principal, _ := auth.PrincipalFrom(c.Request.Context())
+	return principal
+}
@@ -0,0 +1,233 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"github.com/brianvoe/gofakeit"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/auth"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	productID := uuid.MustParse(gofakeit.UUID())
+	order := domain.Order{ID: uuid.MustParse(gofakeit.UUID()), OwnerID: "owner1"}
+
+	tests := []struct {
+		name            string
+		method          string
+		url             string
+		token           string
+		cartMockSetup   func(s *service.MockCartService)
+		orderMockSetup  func(s *service.MockOrderService)
+		wantStatus      int
+		wantProblemType string
+	}{
+		{
+			name:            "missing token",
+			url:             "/carts/owner1",
+			wantStatus:      http.StatusUnauthorized,
+			wantProblemType: dto.ProblemTypeUnauthorized,
+		},
+		{
+			name:            "invalid signature",
+			url:             "/carts/owner1",
+			token:           signToken(t, "another-secret-another-secret-00", jwt.MapClaims{"sub": "owner1", "exp": inOneHour()}),
+			wantStatus:      http.StatusUnauthorized,
+			wantProblemType: dto.ProblemTypeUnauthorized,
+		},
+		{
+			name:            "expired token",
+			url:             "/carts/owner1",
+			token:           signToken(t, testSecret, jwt.MapClaims{"sub": "owner1", "exp": time.Now().Add(-time.Hour).Unix()}),
+			wantStatus:      http.StatusUnauthorized,
+			wantProblemType: dto.ProblemTypeUnauthorized,
+		},
+		{
+			name:  "own cart",
+			url:   "/carts/owner1",
+			token: bearer(t, "owner1"),
+			cartMockSetup: func(s *service.MockCartService) {
+				s.On("GetCart", mock.Anything, "owner1").Return(domain.Cart{OwnerID: "owner1"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:            "cart of another owner",
+			url:             "/carts/owner1",
+			token:           bearer(t, "owner2"),
+			wantStatus:      http.StatusForbidden,
+			wantProblemType: dto.ProblemTypeForbidden,
+		},
+		{
+			name:  "admin accesses cart of another owner",
+			url:   "/carts/owner1",
+			token: bearer(t, "support", "orders:read", "admin"),
+			cartMockSetup: func(s *service.MockCartService) {
+				s.On("GetCart", mock.Anything, "owner1").Return(domain.Cart{OwnerID: "owner1"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "me cart",
+			url:   "/me/cart",
+			token: bearer(t, "owner1"),
+			cartMockSetup: func(s *service.MockCartService) {
+				s.On("GetCart", mock.Anything, "owner1").Return(domain.Cart{OwnerID: "owner1"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "me cart item",
+			method: http.MethodDelete,
+			url:    "/me/cart/" + productID.String(),
+			token:  bearer(t, "owner1"),
+			cartMockSetup: func(s *service.MockCartService) {
+				s.On("DeleteItem", mock.Anything, "owner1", productID).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:            "orders of another owner",
+			url:             "/owners/owner1/orders",
+			token:           bearer(t, "owner2"),
+			wantStatus:      http.StatusForbidden,
+			wantProblemType: dto.ProblemTypeForbidden,
+		},
+		{
+			name:  "order of another owner is not found",
+			url:   "/orders/" + order.ID.String(),
+			token: bearer(t, "owner2"),
+			orderMockSetup: func(s *service.MockOrderService) {
+				s.On("GetOrder", mock.Anything, order.ID).Return(order, nil)
+			},
+			wantStatus:      http.StatusNotFound,
+			wantProblemType: dto.ProblemTypeOrderNotFound,
+		},
+		{
+			name:            "owner cannot change order status",
+			method:          http.MethodPatch,
+			url:             "/orders/" + order.ID.String() + "/status",
+			token:           bearer(t, "owner1"),
+			wantStatus:      http.StatusForbidden,
+			wantProblemType: dto.ProblemTypeForbidden,
+		},
+		{
+			name:  "status history of own order",
+			url:   "/orders/" + order.ID.String() + "/status/history",
+			token: bearer(t, "owner1"),
+			orderMockSetup: func(s *service.MockOrderService) {
+				s.On("GetStatusHistory", mock.Anything, order.ID).Return(order, []domain.OrderStatusChange{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "status history of another owner is not found",
+			url:   "/orders/" + order.ID.String() + "/status/history",
+			token: bearer(t, "owner2"),
+			orderMockSetup: func(s *service.MockOrderService) {
+				s.On("GetStatusHistory", mock.Anything, order.ID).Return(order, []domain.OrderStatusChange{}, nil)
+			},
+			wantStatus:      http.StatusNotFound,
+			wantProblemType: dto.ProblemTypeOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cartService := new(service.MockCartService)
+			if tt.cartMockSetup != nil {
+				tt.cartMockSetup(cartService)
+			}
+
+			orderService := new(service.MockOrderService)
+			if tt.orderMockSetup != nil {
+				tt.orderMockSetup(orderService)
+			}
+
+			cartHandler, err := rest.NewCart(cartService)
+			require.NoError(t, err)
+
+			orderHandler, err := rest.NewOrder(orderService)
+			require.NoError(t, err)
+
+			router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t))
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantProblemType != "" {
+				var problem dto.Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, tt.wantProblemType, problem.Type)
+			}
+
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.True(t, strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "Bearer"))
+			}
+
+			cartService.AssertExpectations(t)
+			orderService.AssertExpectations(t)
+		})
+	}
+}
+
+func newVerifier(t *testing.T) *auth.Verifier {
+	t.Helper()
+
+	verifier, err := auth.NewVerifier(auth.WithHMACSecret([]byte(testSecret)), auth.WithAdminScope("admin"))
+	require.NoError(t, err)
+
+	return verifier
+}
+
+// bearer returns the Authorization header value with a valid token of the subject.
+func bearer(t *testing.T, subject string, scopes ...string) string {
+	t.Helper()
+
+	return signToken(t, testSecret, jwt.MapClaims{
+		"sub":   subject,
+		"exp":   inOneHour(),
+		"scope": strings.Join(scopes, " "),
+	})
+}
+
+func signToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return "Bearer " + token
+}
+
+func inOneHour() int64 {
+	return time.Now().Add(time.Hour).Unix()
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/nikolayk812/go-tests/internal/rest/mapper"
 	"github.com/nikolayk812/go-tests/internal/service"
 	"github.com/nikolayk812/go-tests/pkg/dto"
+	"golang.org/x/text/currency"
 	"net/http"
 )
 
@@ -22,19 +23,45 @@ func NewCart(service service.CartService) (*CartHandler, error) {
 	return &CartHandler{service: service}, nil
 }
 
+// GetCart responds with the cart and its version as the ETag, see IfMatch. It responds with 304 to a matching
+// If-None-Match unless the response also depends on the catalog or the exchange rates, i.e. price changes are flagged
+// or a display currency is requested, as they change without a change of the cart version.
 func (h *CartHandler) GetCart(c *gin.Context) {
 	ownerID := c.Param("owner_id")
 
+	var displayCurrency *currency.Unit
+	if currencyStr := c.Query("currency"); currencyStr != "" {
+		unit := mapper.CurrencyFromDTO(currencyStr)
+		displayCurrency = &unit
+	}
+
 	ctx := c.Request.Context()
 	cart, err := h.service.GetCart(ctx, ownerID)
 	if err != nil {
 		_ = c.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "An unexpected error occurred"})
+		return
+	}
+
+	c.Header("ETag", cartETag(cart.Version))
+
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	if ifNoneMatch != "" && displayCurrency == nil && !cart.HasPriceChanges() && noneMatch(ifNoneMatch, cart.Version) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
 	cartDTO := mapper.CartToDTO(cart)
 
+	if displayCurrency != nil {
+		total, err := h.service.ConvertTotal(ctx, cart, *displayCurrency)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+
+		cartDTO.DisplayTotal = mapper.ConvertedTotalToDTO(total)
+	}
+
 	c.JSON(http.StatusOK, cartDTO)
 }
 
@@ -42,29 +69,37 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 	ownerID := c.Param("owner_id")
 
 	var itemDTO dto.CartItem
-	if err := c.BindJSON(&itemDTO); err != nil {
-		_ = c.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot parse request body"})
+	if err := c.ShouldBindJSON(&itemDTO); err != nil {
+		_ = c.Error(badRequest("cannot parse request body", err))
 		return
 	}
 
-	item, err := mapper.CartItemFromDTO(itemDTO)
-	if err != nil {
-		_ = c.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
-		return
-	}
+	item := mapper.CartItemFromDTO(itemDTO)
 
 	ctx := c.Request.Context()
 	if err := h.service.AddItem(ctx, ownerID, item); err != nil {
 		_ = c.Error(err)
+		return
+	}
 
-		if errors.Is(err, service.ErrCartDuplicateItem) {
-			c.JSON(http.StatusConflict, gin.H{"error": "item already exists in the cart"})
-			return
-		}
+	c.Status(http.StatusCreated)
+}
+
+// AddItems adds a batch of items, either all of them or none.
+func (h *CartHandler) AddItems(c *gin.Context) {
+	ownerID := c.Param("owner_id")
 
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "An unexpected error occurred"})
+	var batchDTO dto.CartItemBatch
+	if err := c.ShouldBindJSON(&batchDTO); err != nil {
+		_ = c.Error(badRequest("cannot parse request body", err))
+		return
+	}
+
+	items := mapper.CartItemsFromDTO(batchDTO)
+
+	ctx := c.Request.Context()
+	if err := h.service.AddItems(ctx, ownerID, items); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -77,22 +112,112 @@ func (h *CartHandler) DeleteItem(c *gin.Context) {
 
 	productUUID, err := uuid.Parse(productID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product_id"})
+		_ = c.Error(badRequest("invalid product_id", err))
 		return
 	}
 
 	ctx := c.Request.Context()
 	if err := h.service.DeleteItem(ctx, ownerID, productUUID); err != nil {
 		_ = c.Error(err)
+		return
+	}
 
-		if errors.Is(err, service.ErrCartItemNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "cart item not found"})
-			return
-		}
+	c.Status(http.StatusNoContent)
+}
 
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "An unexpected error occurred"})
+func (h *CartHandler) DeleteItems(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	var idsDTO dto.CartItemIDs
+	if err := c.ShouldBindJSON(&idsDTO); err != nil {
+		_ = c.Error(badRequest("cannot parse request body", err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	deleted, err := h.service.DeleteItems(ctx, ownerID, idsDTO.ProductIDs)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CartItemsDeleted{Deleted: deleted})
+}
+
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	ctx := c.Request.Context()
+	if err := h.service.ClearCart(ctx, ownerID); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+func (h *CartHandler) SetItemQuantity(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+	productID := c.Param("product_id")
+
+	productUUID, err := uuid.Parse(productID)
+	if err != nil {
+		_ = c.Error(badRequest("invalid product_id", err))
+		return
+	}
+
+	var quantityDTO dto.CartItemQuantity
+	if err := c.ShouldBindJSON(&quantityDTO); err != nil {
+		_ = c.Error(badRequest("cannot parse request body", err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.SetItemQuantity(ctx, ownerID, productUUID, *quantityDTO.Quantity); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CartHandler) Checkout(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	ctx := c.Request.Context()
+	order, err := h.service.Checkout(ctx, ownerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	orderDTO := mapper.OrderToDTO(order)
+
+	c.Header("Location", "/orders/"+order.ID.String())
+	c.JSON(http.StatusCreated, orderDTO)
+}
+
+func (h *CartHandler) Reprice(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	var repriceDTO dto.Reprice
+	if err := c.ShouldBindJSON(&repriceDTO); err != nil {
+		_ = c.Error(badRequest("cannot parse request body", err))
+		return
+	}
+
+	prices := mapper.ItemPricesFromDTO(repriceDTO)
+
+	ctx := c.Request.Context()
+	cart, err := h.service.Reprice(ctx, ownerID, prices)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", cartETag(cart.Version))
+
+	cartDTO := mapper.CartToDTO(cart)
+
+	c.JSON(http.StatusOK, cartDTO)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/uuid"
 	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/repository/repotest"
 	"github.com/nikolayk812/go-tests/internal/rest"
 	"github.com/nikolayk812/go-tests/internal/rest/mapper"
 	"github.com/nikolayk812/go-tests/internal/service"
@@ -18,7 +19,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
-	"golang.org/x/text/currency"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -87,14 +87,15 @@ func TestCartHandler_GetCart(t *testing.T) {
 
 			recorder := httptest.NewRecorder()
 
-			c, _ := gin.CreateTestContext(recorder)
-			c.Params = gin.Params{gin.Param{Key: "owner_id", Value: tt.ownerID}}
+			// errors are rendered by the middleware
+			_, engine := gin.CreateTestContext(recorder)
+			engine.Use(rest.ErrorHandler())
+			engine.GET("/carts/:owner_id", handler.GetCart)
 
-			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			req, err := http.NewRequest(http.MethodGet, "/carts/"+tt.ownerID, nil)
 			require.NoError(t, err)
-			c.Request = req
 
-			handler.GetCart(c)
+			engine.ServeHTTP(recorder, req)
 
 			assert.Equal(t, tt.wantStatus, recorder.Code)
 
@@ -124,14 +125,13 @@ func fakeCartItem() domain.CartItem {
 
 	price := gofakeit.Price(1, 100)
 
-	currencyUnit := currency.MustParseISO(gofakeit.CurrencyShort())
+	currencyUnit := repotest.FakeCurrency()
 
 	return domain.CartItem{
 		ProductID: productID,
-		Price: domain.Money{
-			Amount:   decimal.NewFromFloat(price),
-			Currency: currencyUnit,
-		},
+		// valid prices have no more decimal places than the currency minor units
+		Price:    domain.NewMoney(decimal.NewFromFloat(price), currencyUnit).Round(),
+		Quantity: gofakeit.Number(1, 5),
 	}
 }
 
@@ -210,13 +210,13 @@ func TestCartHandler_GetCart2(t *testing.T) {
 
 			apitest.New().
 				HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					c, _ := gin.CreateTestContext(w)
-					c.Request = r
-					c.Params = gin.Params{gin.Param{Key: "owner_id", Value: tt.ownerID}}
+					_, engine := gin.CreateTestContext(w)
+					engine.Use(rest.ErrorHandler())
+					engine.GET("/carts/:owner_id", handler.GetCart)
 
-					handler.GetCart(c)
+					engine.ServeHTTP(w, r)
 				}).
-				Get("/").
+				Get("/carts/" + tt.ownerID).
 				Expect(t).
 				Status(tt.wantStatus).
 				Assert(func(res *http.Response, r *http.Request) error {
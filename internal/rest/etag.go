@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"strconv"
+	"strings"
+)
+
+// cartETag is the strong entity tag of the cart version, e.g. "3".
+func cartETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// IfMatch makes the cart changes of the request conditional on the If-Match header, see service.WithExpectedVersions.
+// "*" matches every version, as a cart always exists. Weak and malformed tags never match, as If-Match uses
+// the strong comparison, so the change fails with 412 rather than overwrites the cart.
+func IfMatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := strings.TrimSpace(c.GetHeader("If-Match"))
+		if header == "" || header == "*" {
+			c.Next()
+			return
+		}
+
+		versions := make([]int64, 0)
+		for _, tag := range strings.Split(header, ",") {
+			if version, ok := parseCartETag(strings.TrimSpace(tag)); ok {
+				versions = append(versions, version)
+			}
+		}
+
+		c.Request = c.Request.WithContext(service.WithExpectedVersions(c.Request.Context(), versions))
+
+		c.Next()
+	}
+}
+
+// noneMatch tells whether the If-None-Match header matches the cart version, weak tags match as well.
+func noneMatch(header string, version int64) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+
+		if v, ok := parseCartETag(tag); ok && v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseCartETag(tag string) (int64, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version < 0 {
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,141 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/brianvoe/gofakeit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCartHandler_GetCart_ETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cart := domain.Cart{OwnerID: "owner1", Items: []domain.CartItem{fakeCartItem()}, Version: 3}
+
+	changedCart := cart
+	changedCart.Items = []domain.CartItem{fakeCartItem()}
+	changedCart.Items[0].PriceChange = &domain.PriceChange{}
+
+	tests := []struct {
+		name        string
+		cart        domain.Cart
+		url         string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "no condition", cart: cart, wantStatus: http.StatusOK},
+		{name: "not modified", cart: cart, ifNoneMatch: `"3"`, wantStatus: http.StatusNotModified},
+		{name: "weak tag in list", cart: cart, ifNoneMatch: `"1", W/"3"`, wantStatus: http.StatusNotModified},
+		{name: "any", cart: cart, ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "modified", cart: cart, ifNoneMatch: `"2"`, wantStatus: http.StatusOK},
+		{name: "price changes flagged", cart: changedCart, ifNoneMatch: `"3"`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(service.MockCartService)
+			mockService.On("GetCart", mock.Anything, "owner1").Return(tt.cart, nil)
+
+			router := newRouter(t, mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/carts/owner1", nil)
+			req.Header.Set("Authorization", bearer(t, "owner1"))
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	productID := uuid.MustParse(gofakeit.UUID())
+
+	tests := []struct {
+		name         string
+		ifMatch      string
+		err          error
+		wantVersions []int64 // nil for an unconditional change
+		wantStatus   int
+	}{
+		{name: "unconditional", wantStatus: http.StatusNoContent},
+		{name: "any", ifMatch: "*", wantStatus: http.StatusNoContent},
+		{name: "single tag", ifMatch: `"3"`, wantVersions: []int64{3}, wantStatus: http.StatusNoContent},
+		{name: "tag list", ifMatch: `"3", "4"`, wantVersions: []int64{3, 4}, wantStatus: http.StatusNoContent},
+		{
+			name:         "weak and malformed tags never match",
+			ifMatch:      `W/"3", 3, "x"`,
+			wantVersions: []int64{},
+			err:          fmt.Errorf("uow.WithTx: %w", service.ErrCartVersionMismatch),
+			wantStatus:   http.StatusPreconditionFailed,
+		},
+		{
+			name:         "cart changed",
+			ifMatch:      `"2"`,
+			wantVersions: []int64{2},
+			err:          fmt.Errorf("uow.WithTx: %w", service.ErrCartVersionMismatch),
+			wantStatus:   http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotVersions []int64
+				conditional bool
+			)
+
+			mockService := new(service.MockCartService)
+			mockService.On("DeleteItem", mock.Anything, "owner1", productID).
+				Run(func(args mock.Arguments) {
+					gotVersions, conditional = service.ExpectedVersionsFrom(args.Get(0).(context.Context))
+				}).
+				Return(tt.err)
+
+			router := newRouter(t, mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodDelete, "/carts/owner1/"+productID.String(), nil)
+			req.Header.Set("Authorization", bearer(t, "owner1"))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantVersions != nil, conditional)
+			assert.Equal(t, tt.wantVersions, gotVersions)
+
+			if tt.wantStatus == http.StatusPreconditionFailed {
+				var problem dto.Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, dto.ProblemTypeCartVersionMismatch, problem.Type)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
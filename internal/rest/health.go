@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/logger"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultCheckTimeout bounds a readiness check, probes of load balancers time out after a few seconds.
+const defaultCheckTimeout = 2 * time.Second
+
+// HealthCheck checks a dependency of the service, nil means the dependency is available.
+type HealthCheck func(ctx context.Context) error
+
+type HealthHandler struct {
+	checks       map[string]HealthCheck
+	checkTimeout time.Duration
+
+	draining atomic.Bool
+}
+
+// NewHealth returns the handler of the liveness and readiness probes, the checks by name run on every readiness probe.
+func NewHealth(checks map[string]HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks, checkTimeout: defaultCheckTimeout}
+}
+
+// Drain fails the readiness probe from now on, so that load balancers stop routing requests
+// to the service while it shuts down.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live responds with 200 as long as the server handles requests, the dependencies are not checked,
+// so that an unavailable database does not get the service restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.Health{Status: dto.HealthStatusUp})
+}
+
+// Ready responds with 200 if all the checks pass, otherwise with 503 and the failed checks.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, dto.Health{Status: dto.HealthStatusDraining})
+		return
+	}
+
+	health := h.check(c.Request.Context())
+
+	status := http.StatusOK
+	if health.Status != dto.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+
+		logger.From(c.Request.Context()).Warn("readiness check failed", "checks", health.Checks)
+	}
+
+	c.JSON(status, health)
+}
+
+// check runs the checks concurrently, so that the probe takes as long as the slowest check.
+func (h *HealthHandler) check(ctx context.Context) dto.Health {
+	health := dto.Health{
+		Status: dto.HealthStatusUp,
+		Checks: make(map[string]dto.HealthCheck, len(h.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := h.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			health.Checks[name] = result
+			if result.Status != dto.HealthStatusUp {
+				health.Status = dto.HealthStatusDown
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return health
+}
+
+func (h *HealthHandler) run(ctx context.Context, check HealthCheck) dto.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, h.checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	latency := time.Since(start)
+
+	result := dto.HealthCheck{
+		Status:    dto.HealthStatusUp,
+		LatencyMS: float64(latency.Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = dto.HealthStatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,117 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		url        string
+		checks     map[string]rest.HealthCheck
+		drain      bool
+		wantStatus int
+		wantHealth dto.Health
+	}{
+		{
+			name:       "live",
+			url:        "/health/live",
+			checks:     map[string]rest.HealthCheck{"database": down},
+			wantStatus: http.StatusOK,
+			wantHealth: dto.Health{Status: dto.HealthStatusUp},
+		},
+		{
+			name:       "ready",
+			url:        "/health/ready",
+			checks:     map[string]rest.HealthCheck{"database": up, "schema": up},
+			wantStatus: http.StatusOK,
+			wantHealth: dto.Health{
+				Status: dto.HealthStatusUp,
+				Checks: map[string]dto.HealthCheck{
+					"database": {Status: dto.HealthStatusUp},
+					"schema":   {Status: dto.HealthStatusUp},
+				},
+			},
+		},
+		{
+			name:       "database down",
+			url:        "/health/ready",
+			checks:     map[string]rest.HealthCheck{"database": down, "schema": up},
+			wantStatus: http.StatusServiceUnavailable,
+			wantHealth: dto.Health{
+				Status: dto.HealthStatusDown,
+				Checks: map[string]dto.HealthCheck{
+					"database": {Status: dto.HealthStatusDown, Error: "connection refused"},
+					"schema":   {Status: dto.HealthStatusUp},
+				},
+			},
+		},
+		{
+			name:       "draining",
+			url:        "/health/ready",
+			checks:     map[string]rest.HealthCheck{"database": up},
+			drain:      true,
+			wantStatus: http.StatusServiceUnavailable,
+			wantHealth: dto.Health{Status: dto.HealthStatusDraining},
+		},
+		{
+			name:       "live while draining",
+			url:        "/health",
+			drain:      true,
+			wantStatus: http.StatusOK,
+			wantHealth: dto.Health{Status: dto.HealthStatusUp},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := rest.NewHealth(tt.checks)
+			if tt.drain {
+				health.Drain()
+			}
+
+			cartHandler, err := rest.NewCart(new(service.MockCartService))
+			require.NoError(t, err)
+
+			orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+			require.NoError(t, err)
+
+			router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t), rest.WithHealth(health))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var got dto.Health
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+
+			// the latency varies from run to run
+			for name, check := range got.Checks {
+				assert.GreaterOrEqual(t, check.LatencyMS, 0.0)
+
+				check.LatencyMS = 0
+				got.Checks[name] = check
+			}
+
+			assert.Equal(t, tt.wantHealth, got)
+		})
+	}
+}
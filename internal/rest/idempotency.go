@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/logger"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"io"
+	"net/http"
+	"slices"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayedHeader is set on the replayed responses
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotentMethods are the methods which honor the Idempotency-Key header, the others are idempotent anyway.
+var idempotentMethods = []string{http.MethodPost, http.MethodPatch, http.MethodDelete}
+
+// replayedHeaders are stored with a response and replayed with it, the others are set by every request anew.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// Idempotency makes the POST, PATCH and DELETE requests with the Idempotency-Key header safe to retry:
+// the response of a request is stored and replayed to the retries with the same key, a retry with the key
+// and a different request is rejected with 422 and a retry while the request is in progress with 409.
+// The keys are scoped to the subject of the token, so it must run after Authenticate.
+//
+// Only the successful responses are stored, a failed request changes nothing, so a retry runs it again.
+func Idempotency(svc service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" || !slices.Contains(idempotentMethods, c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			_ = c.Error(badRequest("cannot read request body", err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scope := principalFrom(c).Subject
+
+		response, err := svc.Begin(ctx, domain.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: fingerprint(c.Request, body),
+		})
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if response != nil {
+			replay(c, *response)
+			return
+		}
+
+		// the outcome is stored even if the client is gone, which is when it retries
+		ctx = context.WithoutCancel(ctx)
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			// e.g. on a panic, the key of a request which did not complete is released
+			if !completed {
+				release(ctx, svc, scope, key)
+			}
+		}()
+
+		c.Next()
+
+		// errors are rendered by ErrorHandler after this middleware returns
+		if len(c.Errors) > 0 || recorder.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		stored := domain.IdempotentResponse{
+			StatusCode: recorder.Status(),
+			Header:     make(map[string]string),
+			Body:       recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				stored.Header[name] = value
+			}
+		}
+
+		if err := svc.Complete(ctx, scope, key, stored); err != nil {
+			logger.From(ctx).Error("idempotent response not stored", "err", err)
+			return
+		}
+
+		completed = true
+	}
+}
+
+// fingerprint identifies the request by its method, path, query and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(c *gin.Context, response domain.IdempotentResponse) {
+	for name, value := range response.Header {
+		c.Header(name, value)
+	}
+	c.Header(idempotentReplayedHeader, "true")
+
+	c.Status(response.StatusCode)
+	c.Writer.WriteHeaderNow()
+
+	if _, err := c.Writer.Write(response.Body); err != nil {
+		_ = c.Error(fmt.Errorf("c.Writer.Write: %w", err))
+	}
+
+	c.Abort()
+}
+
+// release logs the failure only, the key is then purged once it expires.
+func release(ctx context.Context, svc service.IdempotencyService, scope, key string) {
+	if err := svc.Release(ctx, scope, key); err != nil {
+		logger.From(ctx).Error("idempotency key not released", "err", err)
+	}
+}
+
+// responseRecorder keeps a copy of the response body.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,233 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/brianvoe/gofakeit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	productID := uuid.MustParse(gofakeit.UUID())
+	body := fmt.Sprintf(`{"product_ids":["%s"]}`, productID)
+	key := gofakeit.UUID()
+
+	isKey := mock.MatchedBy(func(k domain.IdempotencyKey) bool {
+		return k.Scope == "owner1" && k.Key == key && len(k.Fingerprint) == 64
+	})
+
+	stored := domain.IdempotentResponse{
+		StatusCode: http.StatusOK,
+		Header:     map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       []byte(`{"deleted":1}`),
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		key           string
+		mockSetup     func(cartService *service.MockCartService, svc *service.MockIdempotencyService)
+		wantStatus    int
+		wantBody      string
+		wantProblem   string
+		wantReplayed  bool
+		wantCompleted bool
+	}{
+		{
+			name:   "no key",
+			method: http.MethodDelete,
+			mockSetup: func(cartService *service.MockCartService, svc *service.MockIdempotencyService) {
+				cartService.On("DeleteItems", mock.Anything, "owner1", []uuid.UUID{productID}).Return(1, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"deleted":1}`,
+		},
+		{
+			name:   "first request",
+			method: http.MethodDelete,
+			key:    key,
+			mockSetup: func(cartService *service.MockCartService, svc *service.MockIdempotencyService) {
+				svc.On("Begin", mock.Anything, isKey).Return(nil, nil)
+				cartService.On("DeleteItems", mock.Anything, "owner1", []uuid.UUID{productID}).Return(1, nil)
+				svc.On("Complete", mock.Anything, "owner1", key, stored).Return(nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantBody:      `{"deleted":1}`,
+			wantCompleted: true,
+		},
+		{
+			name:   "retry replayed",
+			method: http.MethodDelete,
+			key:    key,
+			mockSetup: func(cartService *service.MockCartService, svc *service.MockIdempotencyService) {
+				svc.On("Begin", mock.Anything, isKey).Return(&stored, nil)
+			},
+			wantStatus:   http.StatusOK,
+			wantBody:     `{"deleted":1}`,
+			wantReplayed: true,
+		},
+		{
+			name:   "failed request released",
+			method: http.MethodDelete,
+			key:    key,
+			mockSetup: func(cartService *service.MockCartService, svc *service.MockIdempotencyService) {
+				svc.On("Begin", mock.Anything, isKey).Return(nil, nil)
+				cartService.On("DeleteItems", mock.Anything, "owner1", []uuid.UUID{productID}).
+					Return(0, errors.New("db down"))
+				svc.On("Release", mock.Anything, "owner1", key).Return(nil)
+			},
+			wantStatus:  http.StatusInternalServerError,
+			wantProblem: dto.ProblemTypeInternal,
+		},
+		{
+			name:   "key reused",
+			method: http.MethodDelete,
+			key:    key,
+			mockSetup: func(cartService *service.MockCartService, svc *service.MockIdempotencyService) {
+				svc.On("Begin", mock.Anything, isKey).Return(nil, service.ErrIdempotencyKeyReused)
+			},
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantProblem: dto.ProblemTypeIdempotencyKeyReused,
+		},
+		{
+			name:   "in progress",
+			method: http.MethodDelete,
+			key:    key,
+			mockSetup: func(cartService *service.MockCartService, svc *service.MockIdempotencyService) {
+				svc.On("Begin", mock.Anything, isKey).Return(nil, service.ErrIdempotencyKeyInProgress)
+			},
+			wantStatus:  http.StatusConflict,
+			wantProblem: dto.ProblemTypeIdempotencyKeyInProgress,
+		},
+		{
+			name:   "ignored on GET",
+			method: http.MethodGet,
+			key:    key,
+			mockSetup: func(cartService *service.MockCartService, svc *service.MockIdempotencyService) {
+				cartService.On("GetCart", mock.Anything, "owner1").Return(domain.Cart{OwnerID: "owner1"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCartService := new(service.MockCartService)
+			mockIdempotency := new(service.MockIdempotencyService)
+
+			tt.mockSetup(mockCartService, mockIdempotency)
+
+			cartHandler, err := rest.NewCart(mockCartService)
+			require.NoError(t, err)
+
+			orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+			require.NoError(t, err)
+
+			router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t), rest.WithIdempotency(mockIdempotency))
+
+			url := "/carts/owner1"
+			if tt.method == http.MethodDelete {
+				url += "/items"
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, url, bytes.NewBufferString(body))
+			req.Header.Set("Authorization", bearer(t, "owner1"))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+
+			if tt.wantProblem != "" {
+				var problem dto.Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, tt.wantProblem, problem.Type)
+			}
+
+			if tt.wantReplayed {
+				assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+				assert.Equal(t, stored.Header["Content-Type"], w.Header().Get("Content-Type"))
+			} else {
+				assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+			}
+
+			if !tt.wantCompleted {
+				mockIdempotency.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			mockCartService.AssertExpectations(t)
+			mockIdempotency.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotency_Fingerprint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var fingerprints []string
+
+	mockIdempotency := new(service.MockIdempotencyService)
+	mockIdempotency.On("Begin", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fingerprints = append(fingerprints, args.Get(1).(domain.IdempotencyKey).Fingerprint)
+		}).
+		Return(nil, service.ErrIdempotencyKeyInProgress)
+
+	cartHandler, err := rest.NewCart(new(service.MockCartService))
+	require.NoError(t, err)
+
+	orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+	require.NoError(t, err)
+
+	router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t), rest.WithIdempotency(mockIdempotency))
+
+	requests := []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{http.MethodPost, "/carts/owner1", `{"a":1}`},
+		{http.MethodPost, "/carts/owner1", `{"a":1}`},
+		{http.MethodPost, "/carts/owner1", `{"a":2}`},
+		{http.MethodPost, "/carts/owner1/checkout", `{"a":1}`},
+		{http.MethodDelete, "/carts/owner1", `{"a":1}`},
+	}
+
+	for _, r := range requests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(r.method, r.url, bytes.NewBufferString(r.body))
+		req.Header.Set("Authorization", bearer(t, "owner1"))
+		req.Header.Set("Idempotency-Key", "key1")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusConflict, w.Code)
+	}
+
+	require.Len(t, fingerprints, len(requests))
+	assert.Equal(t, fingerprints[0], fingerprints[1], "same request")
+	assert.NotEqual(t, fingerprints[0], fingerprints[2], "different body")
+	assert.NotEqual(t, fingerprints[0], fingerprints[3], "different path")
+	assert.NotEqual(t, fingerprints[0], fingerprints[4], "different method")
+}
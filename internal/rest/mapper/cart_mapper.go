@@ -1,7 +1,6 @@
 package mapper
 
 import (
-	"fmt"
 	"github.com/nikolayk812/go-tests/internal/domain"
 	"github.com/nikolayk812/go-tests/pkg/dto"
 )
@@ -12,29 +11,70 @@ func CartToDTO(cart domain.Cart) dto.Cart {
 		items = append(items, CartItemToDTO(item))
 	}
 
+	totals := make([]dto.Money, 0)
+	for _, total := range cart.Totals() {
+		totals = append(totals, MoneyToDTO(total))
+	}
+
 	return dto.Cart{
 		OwnerID: cart.OwnerID,
 		Items:   items,
+		Totals:  totals,
 	}
 }
 
 func CartItemToDTO(item domain.CartItem) dto.CartItem {
-	return dto.CartItem{
+	itemDTO := dto.CartItem{
 		ProductID: item.ProductID,
 		Price:     MoneyToDTO(item.Price),
+		Quantity:  item.Quantity,
 		CreatedAt: item.CreatedAt,
 	}
+
+	if item.PriceChange != nil {
+		itemDTO.PriceChange = &dto.PriceChange{
+			OldPrice:  MoneyToDTO(item.PriceChange.OldPrice),
+			NewPrice:  MoneyToDTO(item.PriceChange.NewPrice),
+			ChangedAt: item.PriceChange.ChangedAt,
+		}
+	}
+
+	return itemDTO
 }
 
-func CartItemFromDTO(item dto.CartItem) (domain.CartItem, error) {
-	price, err := MoneyFromDTO(item.Price)
-	if err != nil {
-		return domain.CartItem{}, fmt.Errorf("MoneyFromDTO: %w", err)
+// CartItemFromDTO leaves the price zero if it is omitted in the request.
+func CartItemFromDTO(item dto.CartItem) domain.CartItem {
+	var price domain.Money
+
+	if item.Price.Currency != "" || !item.Price.Amount.IsZero() {
+		price = MoneyFromDTO(item.Price)
 	}
 
 	return domain.CartItem{
 		ProductID: item.ProductID,
 		Price:     price,
+		Quantity:  item.Quantity,
 		CreatedAt: item.CreatedAt,
-	}, nil
+	}
+}
+
+func CartItemsFromDTO(batch dto.CartItemBatch) []domain.CartItem {
+	items := make([]domain.CartItem, 0, len(batch.Items))
+	for _, itemDTO := range batch.Items {
+		items = append(items, CartItemFromDTO(itemDTO))
+	}
+
+	return items
+}
+
+func ItemPricesFromDTO(reprice dto.Reprice) []domain.ItemPrice {
+	prices := make([]domain.ItemPrice, 0, len(reprice.Items))
+	for _, item := range reprice.Items {
+		prices = append(prices, domain.ItemPrice{
+			ProductID: item.ProductID,
+			Price:     MoneyFromDTO(item.Price),
+		})
+	}
+
+	return prices
 }
@@ -0,0 +1,27 @@
+package mapper
+
+import (
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+)
+
+func ConvertedTotalToDTO(total domain.ConvertedTotal) *dto.ConvertedTotal {
+	rates := make([]dto.ExchangeRate, 0, len(total.Rates))
+	for _, rate := range total.Rates {
+		rates = append(rates, ExchangeRateToDTO(rate))
+	}
+
+	return &dto.ConvertedTotal{
+		Total: MoneyToDTO(total.Total),
+		Rates: rates,
+	}
+}
+
+func ExchangeRateToDTO(rate domain.ExchangeRate) dto.ExchangeRate {
+	return dto.ExchangeRate{
+		From:      rate.From.String(),
+		To:        rate.To.String(),
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
@@ -1,8 +1,8 @@
 package mapper
 
 import (
-	"fmt"
 	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/service"
 	"github.com/nikolayk812/go-tests/pkg/dto"
 	"golang.org/x/text/currency"
 )
@@ -14,14 +14,20 @@ func MoneyToDTO(money domain.Money) dto.Money {
 	}
 }
 
-func MoneyFromDTO(money dto.Money) (domain.Money, error) {
-	parsedCurrency, err := currency.ParseISO(money.Currency)
+// MoneyFromDTO maps a currency which is not an ISO 4217 code to service.UnsupportedCurrency.
+func MoneyFromDTO(money dto.Money) domain.Money {
+	return domain.Money{
+		Amount:   money.Amount,
+		Currency: CurrencyFromDTO(money.Currency),
+	}
+}
+
+// CurrencyFromDTO maps a code which is not an ISO 4217 code to service.UnsupportedCurrency.
+func CurrencyFromDTO(code string) currency.Unit {
+	unit, err := currency.ParseISO(code)
 	if err != nil {
-		return domain.Money{}, fmt.Errorf("c currency.ParseISO[%s]: %w", money.Currency, err)
+		return service.UnsupportedCurrency
 	}
 
-	return domain.Money{
-		Amount:   money.Amount,
-		Currency: parsedCurrency,
-	}, nil
+	return unit
 }
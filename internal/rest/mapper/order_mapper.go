@@ -0,0 +1,106 @@
+package mapper
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"strings"
+	"time"
+)
+
+const cursorSeparator = "|"
+
+func OrderToDTO(order domain.Order) dto.Order {
+	items := make([]dto.OrderItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, OrderItemToDTO(item))
+	}
+
+	return dto.Order{
+		ID:        order.ID,
+		OwnerID:   order.OwnerID,
+		Status:    string(order.Status),
+		Items:     items,
+		CreatedAt: order.CreatedAt,
+	}
+}
+
+func OrderItemToDTO(item domain.OrderItem) dto.OrderItem {
+	return dto.OrderItem{
+		ProductID: item.ProductID,
+		Price:     MoneyToDTO(item.Price),
+		Quantity:  item.Quantity,
+		CreatedAt: item.CreatedAt,
+	}
+}
+
+func OrderStatusHistoryToDTO(history []domain.OrderStatusChange) []dto.OrderStatusChange {
+	changes := make([]dto.OrderStatusChange, 0, len(history))
+	for _, change := range history {
+		changes = append(changes, dto.OrderStatusChange{
+			From:      string(change.From),
+			To:        string(change.To),
+			ChangedBy: change.ChangedBy,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+
+	return changes
+}
+
+func OrderPageToDTO(page domain.OrderPage) dto.OrderList {
+	orders := make([]dto.Order, 0, len(page.Orders))
+	for _, order := range page.Orders {
+		orders = append(orders, OrderToDTO(order))
+	}
+
+	return dto.OrderList{
+		Orders:     orders,
+		NextCursor: OrderCursorToDTO(page.Next),
+	}
+}
+
+// OrderCursorToDTO encodes the cursor into an opaque string, nil cursor is encoded as an empty string.
+func OrderCursorToDTO(cursor *domain.OrderCursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	raw := cursor.CreatedAt.Format(time.RFC3339Nano) + cursorSeparator + cursor.ID.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func OrderCursorFromDTO(cursor string) (*domain.OrderCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("base64.DecodeString: %w", err)
+	}
+
+	createdAtStr, idStr, found := strings.Cut(string(raw), cursorSeparator)
+	if !found {
+		return nil, errors.New("cursor separator not found")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("time.Parse[%s]: %w", createdAtStr, err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("uuid.Parse[%s]: %w", idStr, err)
+	}
+
+	return &domain.OrderCursor{
+		CreatedAt: createdAt,
+		ID:        id,
+	}, nil
+}
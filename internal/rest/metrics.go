@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels the requests not matching any route, so that arbitrary paths do not become labels.
+const unmatchedRoute = "unmatched"
+
+// httpMetrics records the http_requests_total counter and the http_request_duration_seconds histogram
+// by method, route template and status.
+func httpMetrics(reg prometheus.Registerer) gin.HandlerFunc {
+	labels := []string{"method", "route", "status"}
+
+	requests := promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status.",
+	}, labels)
+
+	duration := promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, labels)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		labelValues := []string{c.Request.Method, route, strconv.Itoa(c.Writer.Status())}
+
+		requests.WithLabelValues(labelValues...).Inc()
+		duration.WithLabelValues(labelValues...).Observe(time.Since(start).Seconds())
+	}
+}
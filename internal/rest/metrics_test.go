@@ -0,0 +1,70 @@
+package rest_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(service.MockCartService)
+	mockService.On("GetCart", mock.Anything, "owner1").Return(domain.Cart{OwnerID: "owner1"}, nil)
+
+	cartHandler, err := rest.NewCart(mockService)
+	require.NoError(t, err)
+
+	orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t), rest.WithMetrics(registry))
+
+	requests := []struct {
+		url   string
+		token string
+	}{
+		{url: "/carts/owner1", token: bearer(t, "owner1")},
+		{url: "/carts/owner1", token: bearer(t, "owner2")},
+		{url: "/no/such/route"},
+	}
+
+	for _, r := range requests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, r.url, nil)
+		if r.token != "" {
+			req.Header.Set("Authorization", r.token)
+		}
+		router.ServeHTTP(w, req)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/carts/:owner_id",status="200"} 1`,
+		`http_requests_total{method="GET",route="/carts/:owner_id",status="403"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/carts/:owner_id",status="200"} 1`,
+	} {
+		assert.Contains(t, string(body), want)
+	}
+
+	mockService.AssertExpectations(t)
+}
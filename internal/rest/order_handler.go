@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/rest/mapper"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"net/http"
+	"strconv"
+)
+
+type OrderHandler struct {
+	service service.OrderService
+}
+
+func NewOrder(service service.OrderService) (*OrderHandler, error) {
+	if service == nil {
+		return nil, errors.New("service is nil")
+	}
+
+	return &OrderHandler{service: service}, nil
+}
+
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	orderID := c.Param("order_id")
+
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		_ = c.Error(badRequest("invalid order_id", err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, err := h.service.GetOrder(ctx, orderUUID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := authorizeOrder(c, order); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	orderDTO := mapper.OrderToDTO(order)
+
+	c.JSON(http.StatusOK, orderDTO)
+}
+
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	cursor, err := mapper.OrderCursorFromDTO(c.Query("cursor"))
+	if err != nil {
+		_ = c.Error(badRequest("invalid cursor", err))
+		return
+	}
+
+	var limit int
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > service.MaxOrderPageLimit {
+			_ = c.Error(badRequest("invalid limit", err))
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	page, err := h.service.ListOrders(ctx, ownerID, cursor, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	pageDTO := mapper.OrderPageToDTO(page)
+
+	c.JSON(http.StatusOK, pageDTO)
+}
+
+func (h *OrderHandler) UpdateStatus(c *gin.Context) {
+	orderID := c.Param("order_id")
+
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		_ = c.Error(badRequest("invalid order_id", err))
+		return
+	}
+
+	var updateDTO dto.OrderStatusUpdate
+	if err := c.ShouldBindJSON(&updateDTO); err != nil {
+		_ = c.Error(badRequest("cannot parse request body", err))
+		return
+	}
+
+	status, err := domain.ParseOrderStatus(updateDTO.Status)
+	if err != nil {
+		_ = c.Error(badRequest("invalid status", err))
+		return
+	}
+
+	// only admins change the status, see RequireAdmin, the history records who did
+	ctx := c.Request.Context()
+	order, err := h.service.UpdateStatus(ctx, orderUUID, status, principalFrom(c).Subject)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	orderDTO := mapper.OrderToDTO(order)
+
+	c.JSON(http.StatusOK, orderDTO)
+}
+
+func (h *OrderHandler) GetStatusHistory(c *gin.Context) {
+	orderID := c.Param("order_id")
+
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		_ = c.Error(badRequest("invalid order_id", err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, history, err := h.service.GetStatusHistory(ctx, orderUUID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := authorizeOrder(c, order); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	historyDTO := mapper.OrderStatusHistoryToDTO(history)
+
+	c.JSON(http.StatusOK, historyDTO)
+}
+
+// authorizeOrder reports the orders of other owners as not found, so that order IDs cannot be probed.
+func authorizeOrder(c *gin.Context, order domain.Order) error {
+	if !principalFrom(c).CanAccess(order.OwnerID) {
+		return service.ErrOrderNotFound
+	}
+
+	return nil
+}
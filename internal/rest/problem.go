@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"net/http"
+)
+
+// problems maps service and auth errors to problem responses, the first matching error wins.
+var problems = []struct {
+	err     error
+	problem dto.Problem
+}{
+	{errUnauthenticated, dto.Problem{
+		Type: dto.ProblemTypeUnauthorized, Status: http.StatusUnauthorized, Title: "Unauthorized",
+		Detail: "missing or invalid bearer token"}},
+	{errForbidden, dto.Problem{
+		Type: dto.ProblemTypeForbidden, Status: http.StatusForbidden, Title: "Forbidden",
+		Detail: "the token does not grant access to this owner"}},
+	{errAdminRequired, dto.Problem{
+		Type: dto.ProblemTypeForbidden, Status: http.StatusForbidden, Title: "Forbidden",
+		Detail: "the token does not have the admin scope"}},
+	{service.ErrCartDuplicateItem, dto.Problem{
+		Type: dto.ProblemTypeCartDuplicateItem, Status: http.StatusConflict, Title: "Duplicate cart item",
+		Detail: "item already exists in the cart with a different price"}},
+	{service.ErrCartItemNotFound, dto.Problem{
+		Type: dto.ProblemTypeCartItemNotFound, Status: http.StatusNotFound, Title: "Cart item not found"}},
+	{service.ErrCartEmpty, dto.Problem{
+		Type: dto.ProblemTypeCartEmpty, Status: http.StatusUnprocessableEntity, Title: "Cart is empty"}},
+	{service.ErrCartPricesChanged, dto.Problem{
+		Type: dto.ProblemTypeCartPricesChanged, Status: http.StatusConflict, Title: "Cart prices changed",
+		Detail: "cart prices changed, reprice the cart first"}},
+	{service.ErrCartVersionMismatch, dto.Problem{
+		Type: dto.ProblemTypeCartVersionMismatch, Status: http.StatusPreconditionFailed, Title: "Cart changed",
+		Detail: "cart changed since it was read, get the cart and retry"}},
+	{service.ErrProductNotFound, dto.Problem{
+		Type: dto.ProblemTypeProductNotFound, Status: http.StatusNotFound, Title: "Product not found"}},
+	{service.ErrPriceMismatch, dto.Problem{
+		Type: dto.ProblemTypePriceMismatch, Status: http.StatusConflict, Title: "Price mismatch",
+		Detail: "price does not match the current catalog price"}},
+	{service.ErrOrderNotFound, dto.Problem{
+		Type: dto.ProblemTypeOrderNotFound, Status: http.StatusNotFound, Title: "Order not found"}},
+	{service.ErrInvalidStatusTransition, dto.Problem{
+		Type: dto.ProblemTypeInvalidStatusTransition, Status: http.StatusConflict, Title: "Invalid order status transition"}},
+	{service.ErrOrderStatusChanged, dto.Problem{
+		Type: dto.ProblemTypeOrderStatusChanged, Status: http.StatusConflict, Title: "Order status changed",
+		Detail: "order status changed concurrently, please retry"}},
+	{service.ErrExchangeRateNotFound, dto.Problem{
+		Type: dto.ProblemTypeExchangeRateNotFound, Status: http.StatusUnprocessableEntity, Title: "Exchange rate not available"}},
+	{service.ErrIdempotencyKeyReused, dto.Problem{
+		Type: dto.ProblemTypeIdempotencyKeyReused, Status: http.StatusUnprocessableEntity, Title: "Idempotency key reused",
+		Detail: "idempotency key was already sent with a different request"}},
+	{service.ErrIdempotencyKeyInProgress, dto.Problem{
+		Type: dto.ProblemTypeIdempotencyKeyInProgress, Status: http.StatusConflict, Title: "Request in progress",
+		Detail: "request with the idempotency key is in progress, retry later"}},
+}
+
+// ErrorHandler renders the last error added by a handler with c.Error as an RFC 7807 problem,
+// so handlers do not write error responses themselves.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		renderProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// renderProblem writes the problem of err as the response.
+func renderProblem(c *gin.Context, err error) {
+	problem := problemOf(err)
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = requestIDFrom(c)
+
+	c.Header("Content-Type", dto.ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
+
+func problemOf(err error) dto.Problem {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return dto.Problem{
+			Type:   dto.ProblemTypeBadRequest,
+			Status: http.StatusBadRequest,
+			Title:  "Bad request",
+			Detail: reqErr.detail,
+		}
+	}
+
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		params := make([]dto.InvalidParam, 0, len(validationErr.Violations))
+		for _, v := range validationErr.Violations {
+			params = append(params, dto.InvalidParam{Name: v.Field, Reason: v.Reason})
+		}
+
+		return dto.Problem{
+			Type:          dto.ProblemTypeValidation,
+			Status:        http.StatusUnprocessableEntity,
+			Title:         "Validation failed",
+			Detail:        "request has invalid fields",
+			InvalidParams: params,
+		}
+	}
+
+	var batchErr *service.BatchError
+	if errors.As(err, &batchErr) {
+		items := make([]dto.CartItemError, 0, len(batchErr.Items))
+		for _, itemErr := range batchErr.Items {
+			itemProblem := problemOf(itemErr.Err)
+
+			items = append(items, dto.CartItemError{
+				Index:     itemErr.Index,
+				ProductID: itemErr.ProductID,
+				Type:      itemProblem.Type,
+				Error:     itemProblem.Title,
+			})
+		}
+
+		return dto.Problem{
+			Type:   dto.ProblemTypeCartItemsRejected,
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Cart items rejected",
+			Detail: "no items added",
+			Items:  items,
+		}
+	}
+
+	for _, p := range problems {
+		if errors.Is(err, p.err) {
+			return p.problem
+		}
+	}
+
+	// internal details are only logged
+	return dto.Problem{
+		Type:   dto.ProblemTypeInternal,
+		Status: http.StatusInternalServerError,
+		Title:  "Internal server error",
+		Detail: "An unexpected error occurred",
+	}
+}
+
+// requestError is a client error found by a handler before calling the service, e.g. an invalid path param.
+type requestError struct {
+	detail string
+	err    error
+}
+
+// badRequest returns an error rendered as a bad request problem with the detail, err is optional.
+func badRequest(detail string, err error) error {
+	return &requestError{detail: detail, err: err}
+}
+
+func (e *requestError) Error() string {
+	if e.err == nil {
+		return e.detail
+	}
+
+	return e.detail + ": " + e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,241 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/brianvoe/gofakeit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/nikolayk812/go-tests/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	productID := uuid.MustParse(gofakeit.UUID())
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		body        string
+		mockSetup   func(service *service.MockCartService)
+		wantProblem dto.Problem
+	}{
+		{
+			name: "service error",
+			url:  "/carts/owner1/" + productID.String(),
+			mockSetup: func(s *service.MockCartService) {
+				s.On("DeleteItem", mock.Anything, "owner1", productID).
+					Return(fmt.Errorf("repo.DeleteItem: %w", service.ErrCartItemNotFound))
+			},
+			method: http.MethodDelete,
+			wantProblem: dto.Problem{
+				Type:     dto.ProblemTypeCartItemNotFound,
+				Title:    "Cart item not found",
+				Status:   http.StatusNotFound,
+				Instance: "/carts/owner1/" + productID.String(),
+			},
+		},
+		{
+			name:   "invalid path param",
+			method: http.MethodDelete,
+			url:    "/carts/owner1/123",
+			wantProblem: dto.Problem{
+				Type:     dto.ProblemTypeBadRequest,
+				Title:    "Bad request",
+				Status:   http.StatusBadRequest,
+				Detail:   "invalid product_id",
+				Instance: "/carts/owner1/123",
+			},
+		},
+		{
+			name:   "unparsable body",
+			method: http.MethodPost,
+			url:    "/carts/owner1",
+			body:   "{",
+			wantProblem: dto.Problem{
+				Type:     dto.ProblemTypeBadRequest,
+				Title:    "Bad request",
+				Status:   http.StatusBadRequest,
+				Detail:   "cannot parse request body",
+				Instance: "/carts/owner1",
+			},
+		},
+		{
+			name:   "unsupported currency",
+			method: http.MethodPost,
+			url:    "/carts/owner1/items:batch",
+			body: `{"items": [{"product_id": "` + productID.String() + `"},` +
+				`{"product_id": "` + productID.String() + `", "price": {"amount": "1", "currency": "ABC"}}]}`,
+			mockSetup: func(s *service.MockCartService) {
+				// an unknown code is passed to the service as UnsupportedCurrency
+				s.On("AddItems", mock.Anything, "owner1", mock.MatchedBy(func(items []domain.CartItem) bool {
+					return len(items) == 2 && items[1].Price.Currency == service.UnsupportedCurrency
+				})).Return(&service.ValidationError{Violations: []service.Violation{
+					{Field: "items[1].price.currency", Reason: "is not a supported currency"},
+				}})
+			},
+			wantProblem: dto.Problem{
+				Type:     dto.ProblemTypeValidation,
+				Title:    "Validation failed",
+				Status:   http.StatusUnprocessableEntity,
+				Detail:   "request has invalid fields",
+				Instance: "/carts/owner1/items:batch",
+				InvalidParams: []dto.InvalidParam{
+					{Name: "items[1].price.currency", Reason: "is not a supported currency"},
+				},
+			},
+		},
+		{
+			name:   "validation error",
+			method: http.MethodDelete,
+			url:    "/carts/owner1/" + productID.String(),
+			mockSetup: func(s *service.MockCartService) {
+				s.On("DeleteItem", mock.Anything, "owner1", productID).
+					Return(&service.ValidationError{Violations: []service.Violation{
+						{Field: "owner_id", Reason: "is invalid"},
+						{Field: "product_id", Reason: "is empty"},
+					}})
+			},
+			wantProblem: dto.Problem{
+				Type:     dto.ProblemTypeValidation,
+				Title:    "Validation failed",
+				Status:   http.StatusUnprocessableEntity,
+				Detail:   "request has invalid fields",
+				Instance: "/carts/owner1/" + productID.String(),
+				InvalidParams: []dto.InvalidParam{
+					{Name: "owner_id", Reason: "is invalid"},
+					{Name: "product_id", Reason: "is empty"},
+				},
+			},
+		},
+		{
+			name:   "batch error",
+			method: http.MethodPost,
+			url:    "/carts/owner1/items:batch",
+			body:   `{"items": [{"product_id": "` + productID.String() + `"}]}`,
+			mockSetup: func(s *service.MockCartService) {
+				s.On("AddItems", mock.Anything, "owner1", mock.Anything).
+					Return(fmt.Errorf("uow.WithTx: %w", &service.BatchError{Items: []service.ItemError{
+						{Index: 0, ProductID: productID, Err: service.ErrProductNotFound},
+					}}))
+			},
+			wantProblem: dto.Problem{
+				Type:     dto.ProblemTypeCartItemsRejected,
+				Title:    "Cart items rejected",
+				Status:   http.StatusUnprocessableEntity,
+				Detail:   "no items added",
+				Instance: "/carts/owner1/items:batch",
+				Items: []dto.CartItemError{{
+					Index:     0,
+					ProductID: productID,
+					Type:      dto.ProblemTypeProductNotFound,
+					Error:     "Product not found",
+				}},
+			},
+		},
+		{
+			name:   "unexpected error",
+			method: http.MethodGet,
+			url:    "/carts/owner1",
+			mockSetup: func(s *service.MockCartService) {
+				s.On("GetCart", mock.Anything, "owner1").
+					Return(domain.Cart{}, errors.New("db.Query: connection refused"))
+			},
+			wantProblem: dto.Problem{
+				Type:     dto.ProblemTypeInternal,
+				Title:    "Internal server error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "An unexpected error occurred",
+				Instance: "/carts/owner1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(service.MockCartService)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockService)
+			}
+
+			cartHandler, err := rest.NewCart(mockService)
+			require.NoError(t, err)
+
+			orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+			require.NoError(t, err)
+
+			router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(rest.RequestIDHeader, "request-1")
+			req.Header.Set("Authorization", bearer(t, "support", "admin"))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantProblem.Status, w.Code)
+			assert.Equal(t, dto.ProblemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "request-1", w.Header().Get(rest.RequestIDHeader))
+
+			var actual dto.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+
+			tt.wantProblem.RequestID = "request-1"
+			assert.Equal(t, tt.wantProblem, actual)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cartHandler, err := rest.NewCart(new(service.MockCartService))
+	require.NoError(t, err)
+
+	orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+	require.NoError(t, err)
+
+	router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t))
+
+	tests := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{name: "kept", requestID: "abc-123"},
+		{name: "missing", generated: true},
+		{name: "invalid", requestID: "abc 123\n", generated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+			req.Header.Set(rest.RequestIDHeader, tt.requestID)
+			router.ServeHTTP(w, req)
+
+			actual := w.Header().Get(rest.RequestIDHeader)
+			if tt.generated {
+				_, err := uuid.Parse(actual)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.requestID, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"regexp"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+)
+
+// validRequestID limits client supplied request IDs to what is safe to log and echo back.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID keeps the client request ID or generates a new one, the ID is echoed in the response header.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+func requestIDFrom(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
@@ -2,21 +2,142 @@ package rest
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/auth"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 )
 
-func SetupRouter(cartHandler *CartHandler) *gin.Engine {
-	router := gin.Default()
+type routerOptions struct {
+	metrics *prometheus.Registry
+	health  *HealthHandler
 
-	router.Use(gin.Recovery())
-	router.Use(gin.ErrorLogger())
+	idempotency service.IdempotencyService
 
-	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	serviceName    string
+	tracerProvider trace.TracerProvider
+}
+
+// RouterOption configures the router created by SetupRouter.
+type RouterOption func(o *routerOptions)
+
+// WithMetrics records the HTTP metrics on the registry and serves all its metrics on /metrics.
+func WithMetrics(registry *prometheus.Registry) RouterOption {
+	return func(o *routerOptions) {
+		o.metrics = registry
+	}
+}
+
+// WithHealth serves the liveness and readiness probes of the handler, by default the readiness probe checks nothing.
+func WithHealth(health *HealthHandler) RouterOption {
+	return func(o *routerOptions) {
+		o.health = health
+	}
+}
+
+// WithTracing records a span named after the route for every request, the spans continue the trace
+// of the W3C traceparent header of the request.
+func WithTracing(serviceName string, tp trace.TracerProvider) RouterOption {
+	return func(o *routerOptions) {
+		o.serviceName = serviceName
+		o.tracerProvider = tp
+	}
+}
+
+// WithIdempotency replays the stored responses of the authenticated requests retried with the same
+// Idempotency-Key header, see Idempotency.
+func WithIdempotency(svc service.IdempotencyService) RouterOption {
+	return func(o *routerOptions) {
+		o.idempotency = svc
+	}
+}
+
+// SetupRouter requires a bearer token verified by verifier on every route except /health and /metrics.
+func SetupRouter(cartHandler *CartHandler, orderHandler *OrderHandler, verifier *auth.Verifier, opts ...RouterOption) *gin.Engine {
+	o := routerOptions{health: NewHealth(nil)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	router := gin.New()
+
+	router.Use(RequestID())
+
+	if o.tracerProvider != nil {
+		// before the access log, so that the request logger carries the trace ID
+		router.Use(otelgin.Middleware(o.serviceName,
+			otelgin.WithTracerProvider(o.tracerProvider),
+			otelgin.WithPropagators(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))))
+	}
+
+	router.Use(AccessLog())
+	router.Use(Recovery())
 
-	cartGroup := router.Group("carts")
-	cartGroup.GET("/:owner_id", cartHandler.GetCart)
-	cartGroup.POST("/:owner_id", cartHandler.AddItem)
-	cartGroup.DELETE("/:owner_id/:product_id", cartHandler.DeleteItem)
+	if o.metrics != nil {
+		router.Use(httpMetrics(o.metrics))
+	}
+
+	router.Use(ErrorHandler())
+
+	// /health is kept for the existing liveness probes
+	router.GET("/health", o.health.Live)
+	router.GET("/health/live", o.health.Live)
+	router.GET("/health/ready", o.health.Ready)
+
+	if o.metrics != nil {
+		// scraped from the internal network, like /health it is not authenticated
+		router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(o.metrics, promhttp.HandlerOpts{})))
+	}
+
+	authenticated := router.Group("", Authenticate(verifier))
+
+	if o.idempotency != nil {
+		// before IfMatch, so that the retry of a conditional change replays its response rather than fails with 412
+		authenticated.Use(Idempotency(o.idempotency))
+	}
+
+	cartRoutes(authenticated.Group("carts/:owner_id", RequireOwner()), cartHandler)
+	cartRoutes(authenticated.Group("me/cart", Me()), cartHandler)
+
+	orderGroup := authenticated.Group("orders")
+	orderGroup.GET("/:order_id", orderHandler.GetOrder)
+	orderGroup.PATCH("/:order_id/status", RequireAdmin(), orderHandler.UpdateStatus)
+	orderGroup.GET("/:order_id/status/history", orderHandler.GetStatusHistory)
+
+	authenticated.GET("/owners/:owner_id/orders", RequireOwner(), orderHandler.ListOrders)
 
 	return router
 }
+
+// cartRoutes registers the cart routes on a group which sets the :owner_id param.
+func cartRoutes(cartGroup *gin.RouterGroup, cartHandler *CartHandler) {
+	cartGroup.Use(IfMatch())
+
+	cartGroup.GET("", cartHandler.GetCart)
+	cartGroup.POST("", cartHandler.AddItem)
+	cartGroup.POST("/items:batch", customMethod("batch", cartHandler.AddItems))
+	cartGroup.DELETE("", cartHandler.ClearCart)
+	cartGroup.DELETE("/items", cartHandler.DeleteItems)
+	cartGroup.PATCH("/:product_id", cartHandler.SetItemQuantity)
+	cartGroup.DELETE("/:product_id", cartHandler.DeleteItem)
+	cartGroup.POST("/checkout", cartHandler.Checkout)
+	cartGroup.POST("/reprice", cartHandler.Reprice)
+}
+
+// customMethod restricts a "<resource>:<method>" route to the exact method name.
+// gin cannot escape a colon in a path, so ":<method>" is registered as a path param
+// which also matches other suffixes, e.g. "/itemsfoo".
+func customMethod(method string, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param(method) != ":"+method {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		handler(c)
+	}
+}
@@ -3,14 +3,18 @@ package rest_test
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/brianvoe/gofakeit"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
 	"github.com/nikolayk812/go-tests/internal/domain"
 	"github.com/nikolayk812/go-tests/internal/rest/mapper"
+	"github.com/nikolayk812/go-tests/pkg/dto"
 	"golang.org/x/text/currency"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nikolayk812/go-tests/internal/rest"
@@ -32,7 +36,10 @@ func TestCartGroupRoutes(t *testing.T) {
 	handler, err := rest.NewCart(mockService)
 	require.NoError(t, err)
 
-	router := rest.SetupRouter(handler)
+	orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+	require.NoError(t, err)
+
+	router := rest.SetupRouter(handler, orderHandler, newVerifier(t))
 
 	tests := []struct {
 		name       string
@@ -52,6 +59,28 @@ func TestCartGroupRoutes(t *testing.T) {
 			},
 			statusCode: http.StatusOK,
 		},
+		{
+			name:   "GetCart with display currency",
+			method: http.MethodGet,
+			url:    "/carts/" + owner1 + "?currency=EUR",
+			mockFunc: func() {
+				mockService.On("ConvertTotal", mock.Anything, cart1, currency.EUR).
+					Return(domain.ConvertedTotal{Total: domain.Zero(currency.EUR)}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetCart with invalid display currency",
+			method: http.MethodGet,
+			url:    "/carts/" + owner1 + "?currency=EURO",
+			mockFunc: func() {
+				mockService.On("ConvertTotal", mock.Anything, cart1, service.UnsupportedCurrency).
+					Return(domain.ConvertedTotal{}, &service.ValidationError{Violations: []service.Violation{
+						{Field: "currency", Reason: "is not a supported currency"},
+					}})
+			},
+			statusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name:   "AddItem",
 			method: http.MethodPost,
@@ -65,6 +94,27 @@ func TestCartGroupRoutes(t *testing.T) {
 			},
 			statusCode: http.StatusCreated,
 		},
+		{
+			name:   "SetItemQuantity",
+			method: http.MethodPatch,
+			url:    "/carts/123/" + product1UID.String(),
+			body:   map[string]int{"quantity": 2},
+			mockFunc: func() {
+				mockService.On("SetItemQuantity", mock.Anything, "123", product1UID, 2).Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:   "SetItemQuantity with negative quantity",
+			method: http.MethodPatch,
+			url:    "/carts/123/" + product1UID.String(),
+			body:   map[string]int{"quantity": -1},
+			mockFunc: func() {
+				mockService.On("SetItemQuantity", mock.Anything, "123", product1UID, -1).
+					Return(&service.ValidationError{Violations: []service.Violation{{Field: "quantity", Reason: "is negative"}}})
+			},
+			statusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name:   "DeleteItem",
 			method: http.MethodDelete,
@@ -74,6 +124,75 @@ func TestCartGroupRoutes(t *testing.T) {
 			},
 			statusCode: http.StatusNoContent,
 		},
+		{
+			name:   "AddItems",
+			method: http.MethodPost,
+			url:    "/carts/123/items:batch",
+			body:   dto.CartItemBatch{Items: []dto.CartItem{cartItem1DTO}},
+			mockFunc: func() {
+				mockService.On("AddItems", mock.Anything, "123", mock.Anything).Return(nil)
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:   "AddItems with rejected items",
+			method: http.MethodPost,
+			url:    "/carts/456/items:batch",
+			body:   dto.CartItemBatch{Items: []dto.CartItem{cartItem1DTO}},
+			mockFunc: func() {
+				mockService.On("AddItems", mock.Anything, "456", mock.Anything).
+					Return(&service.BatchError{Items: []service.ItemError{
+						{Index: 0, ProductID: product1UID, Err: service.ErrProductNotFound},
+					}})
+			},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "AddItems with unknown custom method",
+			method:     http.MethodPost,
+			url:        "/carts/123/items:merge",
+			body:       dto.CartItemBatch{Items: []dto.CartItem{cartItem1DTO}},
+			mockFunc:   func() {},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "DeleteItems",
+			method: http.MethodDelete,
+			url:    "/carts/123/items",
+			body:   dto.CartItemIDs{ProductIDs: []uuid.UUID{product1UID}},
+			mockFunc: func() {
+				mockService.On("DeleteItems", mock.Anything, "123", []uuid.UUID{product1UID}).Return(1, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "ClearCart",
+			method: http.MethodDelete,
+			url:    "/carts/123",
+			mockFunc: func() {
+				mockService.On("ClearCart", mock.Anything, "123").Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:   "Checkout",
+			method: http.MethodPost,
+			url:    "/carts/123/checkout",
+			mockFunc: func() {
+				mockService.On("Checkout", mock.Anything, "123").Return(domain.Order{OwnerID: "123"}, nil)
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:   "Reprice",
+			method: http.MethodPost,
+			url:    "/carts/123/reprice",
+			body:   dto.Reprice{Items: []dto.ItemPrice{{ProductID: product1UID, Price: cartItem1DTO.Price}}},
+			mockFunc: func() {
+				mockService.On("Reprice", mock.Anything, "123", mock.Anything).Return(domain.Cart{OwnerID: "123"}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
 	}
 
 	for _, tt := range tests {
@@ -90,6 +209,151 @@ func TestCartGroupRoutes(t *testing.T) {
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(tt.method, tt.url, bytes.NewBuffer(bodyBytes))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", bearer(t, "support", "admin"))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderGroupRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	order := domain.Order{
+		ID:      uuid.MustParse(gofakeit.UUID()),
+		OwnerID: gofakeit.UUID(),
+	}
+
+	missingID := uuid.MustParse(gofakeit.UUID())
+
+	cursor := &domain.OrderCursor{
+		CreatedAt: time.Now().UTC(),
+		ID:        order.ID,
+	}
+
+	cartHandler, err := rest.NewCart(new(service.MockCartService))
+	require.NoError(t, err)
+
+	mockService := new(service.MockOrderService)
+	handler, err := rest.NewOrder(mockService)
+	require.NoError(t, err)
+
+	router := rest.SetupRouter(cartHandler, handler, newVerifier(t))
+
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       interface{}
+		mockFunc   func()
+		statusCode int
+	}{
+		{
+			name: "GetOrder",
+			url:  "/orders/" + order.ID.String(),
+			mockFunc: func() {
+				mockService.On("GetOrder", mock.Anything, order.ID).Return(order, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "GetOrder: invalid order_id",
+			url:        "/orders/123",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "GetOrder: not found",
+			url:  "/orders/" + missingID.String(),
+			mockFunc: func() {
+				mockService.On("GetOrder", mock.Anything, missingID).Return(domain.Order{}, service.ErrOrderNotFound)
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "ListOrders",
+			url:  "/owners/" + order.OwnerID + "/orders?limit=5&cursor=" + mapper.OrderCursorToDTO(cursor),
+			mockFunc: func() {
+				mockService.On("ListOrders", mock.Anything, order.OwnerID, mock.MatchedBy(func(c *domain.OrderCursor) bool {
+					return c != nil && c.ID == cursor.ID && c.CreatedAt.Equal(cursor.CreatedAt)
+				}), 5).Return(domain.OrderPage{Orders: []domain.Order{order}}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "ListOrders: invalid cursor",
+			url:        "/owners/" + order.OwnerID + "/orders?cursor=%21%21",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "ListOrders: invalid limit",
+			url:        "/owners/" + order.OwnerID + "/orders?limit=0",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "UpdateStatus",
+			method: http.MethodPatch,
+			url:    "/orders/" + order.ID.String() + "/status",
+			body:   dto.OrderStatusUpdate{Status: "paid"},
+			mockFunc: func() {
+				mockService.On("UpdateStatus", mock.Anything, order.ID, domain.OrderStatusPaid, "support").
+					Return(order, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "UpdateStatus: unknown status",
+			method:     http.MethodPatch,
+			url:        "/orders/" + order.ID.String() + "/status",
+			body:       dto.OrderStatusUpdate{Status: "lost"},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "UpdateStatus: illegal transition",
+			method: http.MethodPatch,
+			url:    "/orders/" + missingID.String() + "/status",
+			body:   dto.OrderStatusUpdate{Status: "delivered"},
+			mockFunc: func() {
+				mockService.On("UpdateStatus", mock.Anything, missingID, domain.OrderStatusDelivered, "support").
+					Return(domain.Order{}, service.ErrInvalidStatusTransition)
+			},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name: "GetStatusHistory",
+			url:  "/orders/" + order.ID.String() + "/status/history",
+			mockFunc: func() {
+				mockService.On("GetStatusHistory", mock.Anything, order.ID).
+					Return(order, []domain.OrderStatusChange{{To: domain.OrderStatusPending, ChangedBy: order.OwnerID}}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			var bodyBytes []byte
+			if tt.body != nil {
+				var err error
+				bodyBytes, err = json.Marshal(tt.body)
+				require.NoError(t, err)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, tt.url, bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", bearer(t, "support", "admin"))
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.statusCode, w.Code)
@@ -0,0 +1,77 @@
+package rest_test
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/rest"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	logs := captureLogs(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var serviceSpan trace.SpanContext
+
+	mockService := new(service.MockCartService)
+	mockService.On("GetCart", mock.Anything, "owner1").
+		Run(func(args mock.Arguments) {
+			serviceSpan = trace.SpanContextFromContext(args.Get(0).(context.Context))
+		}).
+		Return(domain.Cart{OwnerID: "owner1"}, nil)
+
+	cartHandler, err := rest.NewCart(mockService)
+	require.NoError(t, err)
+
+	orderHandler, err := rest.NewOrder(new(service.MockOrderService))
+	require.NoError(t, err)
+
+	router := rest.SetupRouter(cartHandler, orderHandler, newVerifier(t), rest.WithTracing("test", tp))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/carts/owner1", nil)
+	req.Header.Set("Authorization", bearer(t, "owner1"))
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, "/carts/:owner_id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+
+	// the span continues the trace of the caller
+	assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+	assert.Equal(t, parentSpanID, span.Parent().SpanID().String())
+
+	// the handlers run within the route span
+	assert.Equal(t, span.SpanContext(), serviceSpan)
+
+	entries := logEntries(t, logs)
+	require.Len(t, entries, 1)
+	assert.Equal(t, traceID, entries[0]["trace_id"])
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/text/currency"
+)
+
+// Outcomes of the cart operations counted by the cart_operations_total metric.
+const (
+	OutcomeSuccess   = "success"
+	OutcomeInvalid   = "invalid"
+	OutcomeDuplicate = "duplicate"
+	OutcomeNotFound  = "not_found"
+	OutcomeRejected  = "rejected"
+	OutcomeError     = "error"
+)
+
+// cartMetrics counts the operations of the wrapped cart service by outcome.
+type cartMetrics struct {
+	next       CartService
+	operations *prometheus.CounterVec
+}
+
+// NewCartMetrics wraps the cart service with the cart_operations_total{operation,outcome} counter
+// registered on reg.
+func NewCartMetrics(next CartService, reg prometheus.Registerer) (CartService, error) {
+	if next == nil {
+		return nil, errors.New("next is nil")
+	}
+
+	if reg == nil {
+		return nil, errors.New("reg is nil")
+	}
+
+	operations := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cart_operations_total",
+		Help: "Cart operations by outcome.",
+	}, []string{"operation", "outcome"})
+
+	if err := reg.Register(operations); err != nil {
+		return nil, fmt.Errorf("reg.Register: %w", err)
+	}
+
+	return &cartMetrics{next: next, operations: operations}, nil
+}
+
+func (m *cartMetrics) GetCart(ctx context.Context, ownerID string) (domain.Cart, error) {
+	cart, err := m.next.GetCart(ctx, ownerID)
+	m.count("get_cart", err)
+
+	return cart, err
+}
+
+func (m *cartMetrics) AddItem(ctx context.Context, ownerID string, item domain.CartItem) error {
+	err := m.next.AddItem(ctx, ownerID, item)
+	m.count("add_item", err)
+
+	return err
+}
+
+func (m *cartMetrics) AddItems(ctx context.Context, ownerID string, items []domain.CartItem) error {
+	err := m.next.AddItems(ctx, ownerID, items)
+	m.count("add_items", err)
+
+	return err
+}
+
+func (m *cartMetrics) DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) error {
+	err := m.next.DeleteItem(ctx, ownerID, productID)
+	m.count("delete_item", err)
+
+	return err
+}
+
+func (m *cartMetrics) DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	deleted, err := m.next.DeleteItems(ctx, ownerID, productIDs)
+	m.count("delete_items", err)
+
+	return deleted, err
+}
+
+func (m *cartMetrics) ClearCart(ctx context.Context, ownerID string) error {
+	err := m.next.ClearCart(ctx, ownerID)
+	m.count("clear_cart", err)
+
+	return err
+}
+
+func (m *cartMetrics) SetItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) error {
+	err := m.next.SetItemQuantity(ctx, ownerID, productID, quantity)
+	m.count("set_item_quantity", err)
+
+	return err
+}
+
+func (m *cartMetrics) Checkout(ctx context.Context, ownerID string) (domain.Order, error) {
+	order, err := m.next.Checkout(ctx, ownerID)
+	m.count("checkout", err)
+
+	return order, err
+}
+
+func (m *cartMetrics) ConvertTotal(ctx context.Context, cart domain.Cart, to currency.Unit) (domain.ConvertedTotal, error) {
+	total, err := m.next.ConvertTotal(ctx, cart, to)
+	m.count("convert_total", err)
+
+	return total, err
+}
+
+func (m *cartMetrics) Reprice(ctx context.Context, ownerID string, prices []domain.ItemPrice) (domain.Cart, error) {
+	cart, err := m.next.Reprice(ctx, ownerID, prices)
+	m.count("reprice", err)
+
+	return cart, err
+}
+
+func (m *cartMetrics) count(operation string, err error) {
+	m.operations.WithLabelValues(operation, outcomeOf(err)).Inc()
+}
+
+// outcomeOf classifies the error returned by the cart service, unexpected errors are OutcomeError.
+func outcomeOf(err error) string {
+	var validationErr *ValidationError
+	var batchErr *BatchError
+
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.As(err, &validationErr):
+		return OutcomeInvalid
+	case errors.Is(err, ErrCartDuplicateItem):
+		return OutcomeDuplicate
+	case errors.Is(err, ErrCartItemNotFound),
+		errors.Is(err, ErrProductNotFound),
+		errors.Is(err, ErrExchangeRateNotFound):
+		return OutcomeNotFound
+	case errors.As(err, &batchErr),
+		errors.Is(err, ErrPriceMismatch),
+		errors.Is(err, ErrCartEmpty),
+		errors.Is(err, ErrCartPricesChanged),
+		errors.Is(err, ErrCartVersionMismatch):
+		return OutcomeRejected
+	default:
+		return OutcomeError
+	}
+}
@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestCartMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantOutcome string
+	}{
+		{name: "success", wantOutcome: service.OutcomeSuccess},
+		{
+			name:        "invalid",
+			err:         &service.ValidationError{Violations: []service.Violation{{Field: "owner_id", Reason: "is empty"}}},
+			wantOutcome: service.OutcomeInvalid,
+		},
+		{
+			name:        "duplicate",
+			err:         fmt.Errorf("cs.addItem: %w", service.ErrCartDuplicateItem),
+			wantOutcome: service.OutcomeDuplicate,
+		},
+		{name: "product not found", err: service.ErrProductNotFound, wantOutcome: service.OutcomeNotFound},
+		{name: "price mismatch", err: service.ErrPriceMismatch, wantOutcome: service.OutcomeRejected},
+		{
+			name:        "cart version mismatch",
+			err:         fmt.Errorf("uow.WithTx: %w: version 3", service.ErrCartVersionMismatch),
+			wantOutcome: service.OutcomeRejected,
+		},
+		{name: "unexpected error", err: errors.New("repo.AddItem: unexpected error"), wantOutcome: service.OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(service.MockCartService)
+			mockService.On("AddItem", mock.Anything, "owner1", mock.Anything).Return(tt.err)
+
+			registry := prometheus.NewRegistry()
+
+			cs, err := service.NewCartMetrics(mockService, registry)
+			require.NoError(t, err)
+
+			err = cs.AddItem(t.Context(), "owner1", fakeCartItem())
+			assert.Equal(t, tt.err, err)
+
+			expected := fmt.Sprintf(`
+# HELP cart_operations_total Cart operations by outcome.
+# TYPE cart_operations_total counter
+cart_operations_total{operation="add_item",outcome=%q} 1
+`, tt.wantOutcome)
+
+			require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "cart_operations_total"))
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -6,49 +6,158 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/logger"
 	"github.com/nikolayk812/go-tests/internal/port"
 	"github.com/nikolayk812/go-tests/internal/repository"
+	"golang.org/x/text/currency"
 )
 
 //go:generate mockery --name=CartService --structname=MockCartService --output=. --outpkg=service --filename=cart_service_mock.go
 type CartService interface {
 	GetCart(ctx context.Context, ownerID string) (domain.Cart, error)
 	AddItem(ctx context.Context, ownerID string, item domain.CartItem) error
+	AddItems(ctx context.Context, ownerID string, items []domain.CartItem) error
 	DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) error
+	DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error)
+	ClearCart(ctx context.Context, ownerID string) error
+	SetItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) error
+	Checkout(ctx context.Context, ownerID string) (domain.Order, error)
+	ConvertTotal(ctx context.Context, cart domain.Cart, to currency.Unit) (domain.ConvertedTotal, error)
+	Reprice(ctx context.Context, ownerID string, prices []domain.ItemPrice) (domain.Cart, error)
 }
 
 type cartService struct {
-	repo port.CartRepository
+	repo    port.CartRepository
+	uow     port.UnitOfWork
+	rates   port.ExchangeRateProvider
+	catalog port.ProductCatalog
 }
 
-func NewCart(repo port.CartRepository) (CartService, error) {
+func NewCart(
+	repo port.CartRepository,
+	uow port.UnitOfWork,
+	rates port.ExchangeRateProvider,
+	catalog port.ProductCatalog,
+) (CartService, error) {
 	if repo == nil {
 		return nil, errors.New("repo is nil")
 	}
 
-	return &cartService{repo: repo}, nil
+	if uow == nil {
+		return nil, errors.New("uow is nil")
+	}
+
+	if rates == nil {
+		return nil, errors.New("rates is nil")
+	}
+
+	if catalog == nil {
+		return nil, errors.New("catalog is nil")
+	}
+
+	return &cartService{repo: repo, uow: uow, rates: rates, catalog: catalog}, nil
 }
 
+// GetCart returns the cart with the items which price differs from the current catalog price flagged.
 func (cs *cartService) GetCart(ctx context.Context, ownerID string) (domain.Cart, error) {
 	var cart domain.Cart
 
-	if ownerID == "" {
-		return cart, errors.New("ownerID is empty")
+	var v violations
+	v.ownerID(ownerID)
+	if err := v.err(); err != nil {
+		return cart, err
+	}
+
+	cart, err := cs.repo.GetCart(ctx, ownerID)
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("repo.GetCart: %w", err)
+	}
+
+	// items of products removed from the catalog are shown unflagged, checkout rejects them
+	if _, err := flagPriceChanges(ctx, cs.catalog, &cart); err != nil {
+		return domain.Cart{}, fmt.Errorf("flagPriceChanges: %w", err)
 	}
 
-	return cs.repo.GetCart(ctx, ownerID)
+	return cart, nil
 }
 
+// AddItem adds the product to the cart at its current catalog price.
+// The item price is optional, if it is set it must match the catalog price.
 func (cs *cartService) AddItem(ctx context.Context, ownerID string, item domain.CartItem) error {
-	if ownerID == "" {
-		return errors.New("ownerID is empty")
+	var v violations
+	v.ownerID(ownerID)
+	v.cartItem("", item)
+	if err := v.err(); err != nil {
+		return err
 	}
 
-	if item.ProductID == uuid.Nil {
-		return errors.New("productID is empty")
+	return cs.change(ctx, ownerID, func(repo port.Repository) error {
+		return cs.addItem(ctx, repo, ownerID, item)
+	})
+}
+
+// AddItems adds all the items to the cart in a single transaction.
+// If any item is rejected none of the items are added and *BatchError lists the rejected items.
+func (cs *cartService) AddItems(ctx context.Context, ownerID string, items []domain.CartItem) error {
+	var v violations
+	v.ownerID(ownerID)
+	if len(items) == 0 {
+		v.add("items", "is empty")
+	}
+	for i, item := range items {
+		v.cartItem(fmt.Sprintf("items[%d].", i), item)
 	}
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	return cs.change(ctx, ownerID, func(repo port.Repository) error {
+		var batchErr BatchError
+
+		for i, item := range items {
+			err := cs.addItem(ctx, repo, ownerID, item)
+			switch {
+			case err == nil:
+			case errors.Is(err, ErrProductNotFound),
+				errors.Is(err, ErrPriceMismatch),
+				errors.Is(err, ErrCartDuplicateItem):
+				batchErr.Items = append(batchErr.Items, ItemError{Index: i, ProductID: item.ProductID, Err: err})
+			default:
+				return fmt.Errorf("cs.addItem[%d]: %w", i, err)
+			}
+		}
 
-	if err := cs.repo.AddItem(ctx, ownerID, item); err != nil {
+		// rolls back the items added so far
+		if len(batchErr.Items) > 0 {
+			logger.From(ctx).Info("cart items rejected", "items", len(items), "rejected", len(batchErr.Items))
+			return &batchErr
+		}
+
+		return nil
+	})
+}
+
+// addItem adds the validated item at its current catalog price, which is read in the cart change transaction.
+func (cs *cartService) addItem(ctx context.Context, repo port.Repository, ownerID string, item domain.CartItem) error {
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+
+	product, err := repo.GetProduct(ctx, item.ProductID)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return ErrProductNotFound
+		}
+		return fmt.Errorf("repo.GetProduct: %w", err)
+	}
+
+	if item.Price.Currency != (currency.Unit{}) && !item.Price.Equal(product.Price) {
+		return fmt.Errorf("%w: got %s, catalog %s", ErrPriceMismatch, item.Price, product.Price)
+	}
+
+	item.Price = product.Price
+
+	if err := repo.AddItem(ctx, ownerID, item); err != nil {
 		if errors.Is(err, repository.ErrCartDuplicateItem) {
 			return ErrCartDuplicateItem // from service layer
 		}
@@ -59,22 +168,327 @@ func (cs *cartService) AddItem(ctx context.Context, ownerID string, item domain.
 }
 
 func (cs *cartService) DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) error {
-	if ownerID == "" {
-		return errors.New("ownerID is empty")
+	var v violations
+	v.ownerID(ownerID)
+	v.productID("product_id", productID)
+	if err := v.err(); err != nil {
+		return err
 	}
 
-	if productID == uuid.Nil {
-		return errors.New("productID is empty")
+	return cs.change(ctx, ownerID, func(repo port.Repository) error {
+		deleted, err := repo.DeleteItem(ctx, ownerID, productID)
+		if err != nil {
+			return fmt.Errorf("repo.DeleteItem: %w", err)
+		}
+
+		if !deleted {
+			return ErrCartItemNotFound // to return error is decision of service layer
+		}
+
+		return nil
+	})
+}
+
+// DeleteItems removes the products from the cart and returns the number of removed items.
+// Products which are not in the cart are ignored.
+func (cs *cartService) DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	var v violations
+	v.ownerID(ownerID)
+	if len(productIDs) == 0 {
+		v.add("product_ids", "is empty")
+	}
+	for i, productID := range productIDs {
+		v.productID(fmt.Sprintf("product_ids[%d]", i), productID)
 	}
+	if err := v.err(); err != nil {
+		return 0, err
+	}
+
+	var deleted int
 
-	deleted, err := cs.repo.DeleteItem(ctx, ownerID, productID)
+	err := cs.change(ctx, ownerID, func(repo port.Repository) error {
+		var err error
+
+		deleted, err = repo.DeleteItems(ctx, ownerID, productIDs)
+		if err != nil {
+			return fmt.Errorf("repo.DeleteItems: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("repo.DeleteItem: %w", err)
+		return 0, err
 	}
 
-	if !deleted {
-		return ErrCartItemNotFound // to return error is decision of service layer
+	return deleted, nil
+}
+
+// ClearCart removes all the items from the cart, clearing an empty cart is not an error.
+func (cs *cartService) ClearCart(ctx context.Context, ownerID string) error {
+	var v violations
+	v.ownerID(ownerID)
+	if err := v.err(); err != nil {
+		return err
 	}
 
-	return nil
+	return cs.change(ctx, ownerID, func(repo port.Repository) error {
+		if err := repo.ClearCart(ctx, ownerID); err != nil {
+			return fmt.Errorf("repo.ClearCart: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// SetItemQuantity sets the absolute quantity of the cart item, zero quantity removes the item.
+func (cs *cartService) SetItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) error {
+	var v violations
+	v.ownerID(ownerID)
+	v.productID("product_id", productID)
+	v.quantity("quantity", quantity)
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	if quantity == 0 {
+		return cs.DeleteItem(ctx, ownerID, productID)
+	}
+
+	return cs.change(ctx, ownerID, func(repo port.Repository) error {
+		updated, err := repo.UpdateItemQuantity(ctx, ownerID, productID, quantity)
+		if err != nil {
+			return fmt.Errorf("repo.UpdateItemQuantity: %w", err)
+		}
+
+		if !updated {
+			return ErrCartItemNotFound
+		}
+
+		return nil
+	})
+}
+
+// Checkout converts the cart into an order and empties the cart in a single transaction.
+func (cs *cartService) Checkout(ctx context.Context, ownerID string) (domain.Order, error) {
+	var order domain.Order
+
+	var v violations
+	v.ownerID(ownerID)
+	if err := v.err(); err != nil {
+		return order, err
+	}
+
+	err := cs.change(ctx, ownerID, func(repo port.Repository) error {
+		cart, err := repo.GetCart(ctx, ownerID)
+		if err != nil {
+			return fmt.Errorf("repo.GetCart: %w", err)
+		}
+
+		if len(cart.Items) == 0 {
+			return ErrCartEmpty
+		}
+
+		// the prices are read in the transaction which creates the order,
+		// the customer has to accept new prices with Reprice first
+		missing, err := flagPriceChanges(ctx, repo, &cart)
+		if err != nil {
+			return fmt.Errorf("flagPriceChanges: %w", err)
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("%w: %s", ErrProductNotFound, missing[0])
+		}
+
+		if cart.HasPriceChanges() {
+			return ErrCartPricesChanged
+		}
+
+		orderID, err := repo.CreateOrder(ctx, cartToOrder(cart))
+		if err != nil {
+			return fmt.Errorf("repo.CreateOrder: %w", err)
+		}
+
+		// only the items copied into the order are removed from the cart
+		for _, item := range cart.Items {
+			deleted, err := repo.DeleteItem(ctx, ownerID, item.ProductID)
+			if err != nil {
+				return fmt.Errorf("repo.DeleteItem: %w", err)
+			}
+
+			if !deleted {
+				return fmt.Errorf("cart item[%s] removed during checkout", item.ProductID)
+			}
+		}
+
+		order, err = repo.GetOrder(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("repo.GetOrder: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	logger.From(ctx).Info("order created", "order_id", order.ID, "items", len(order.Items))
+
+	return order, nil
+}
+
+// Reprice updates cart items to the prices accepted by the owner, all or nothing.
+// Every accepted price must match the current catalog price.
+func (cs *cartService) Reprice(ctx context.Context, ownerID string, prices []domain.ItemPrice) (domain.Cart, error) {
+	var v violations
+	v.ownerID(ownerID)
+	if len(prices) == 0 {
+		v.add("items", "is empty")
+	}
+	for i, price := range prices {
+		v.productID(fmt.Sprintf("items[%d].product_id", i), price.ProductID)
+		v.money(fmt.Sprintf("items[%d].price", i), price.Price)
+	}
+	if err := v.err(); err != nil {
+		return domain.Cart{}, err
+	}
+
+	err := cs.change(ctx, ownerID, func(repo port.Repository) error {
+		for _, price := range prices {
+			product, err := repo.GetProduct(ctx, price.ProductID)
+			if err != nil {
+				if errors.Is(err, repository.ErrProductNotFound) {
+					return ErrProductNotFound
+				}
+				return fmt.Errorf("repo.GetProduct: %w", err)
+			}
+
+			if !price.Price.Equal(product.Price) {
+				return fmt.Errorf("%w: product %s got %s, catalog %s",
+					ErrPriceMismatch, price.ProductID, price.Price, product.Price)
+			}
+
+			updated, err := repo.UpdateItemPrice(ctx, ownerID, price.ProductID, price.Price)
+			if err != nil {
+				return fmt.Errorf("repo.UpdateItemPrice: %w", err)
+			}
+
+			if !updated {
+				return ErrCartItemNotFound
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	logger.From(ctx).Info("cart repriced", "items", len(prices))
+
+	return cs.GetCart(ctx, ownerID)
+}
+
+// flagPriceChanges sets PriceChange on cart items which price differs from the catalog price,
+// it returns the IDs of the products missing from the catalog, their items are not flagged.
+func flagPriceChanges(ctx context.Context, catalog port.ProductCatalog, cart *domain.Cart) ([]uuid.UUID, error) {
+	if len(cart.Items) == 0 {
+		return nil, nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := catalog.GetProducts(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("catalog.GetProducts: %w", err)
+	}
+
+	productByID := make(map[uuid.UUID]domain.Product, len(products))
+	for _, product := range products {
+		productByID[product.ID] = product
+	}
+
+	var missing []uuid.UUID
+
+	for i, item := range cart.Items {
+		product, ok := productByID[item.ProductID]
+		if !ok {
+			missing = append(missing, item.ProductID)
+			continue
+		}
+
+		if product.Price.Equal(item.Price) {
+			continue
+		}
+
+		cart.Items[i].PriceChange = &domain.PriceChange{
+			OldPrice:  item.Price,
+			NewPrice:  product.Price,
+			ChangedAt: product.UpdatedAt,
+		}
+	}
+
+	return missing, nil
+}
+
+// ConvertTotal converts the cart subtotals into a single currency and sums them up.
+func (cs *cartService) ConvertTotal(ctx context.Context, cart domain.Cart, to currency.Unit) (domain.ConvertedTotal, error) {
+	var v violations
+	v.currency("currency", to)
+	if err := v.err(); err != nil {
+		return domain.ConvertedTotal{}, err
+	}
+
+	converted := domain.ConvertedTotal{
+		Total: domain.Zero(to),
+		Rates: make([]domain.ExchangeRate, 0),
+	}
+
+	for _, subtotal := range cart.Totals() {
+		if subtotal.Currency != to {
+			rate, err := cs.rates.GetRate(ctx, subtotal.Currency, to)
+			if err != nil {
+				if errors.Is(err, repository.ErrExchangeRateNotFound) {
+					return domain.ConvertedTotal{}, fmt.Errorf("%w: %s to %s", ErrExchangeRateNotFound, subtotal.Currency, to)
+				}
+				return domain.ConvertedTotal{}, fmt.Errorf("rates.GetRate: %w", err)
+			}
+
+			subtotal, err = rate.Convert(subtotal)
+			if err != nil {
+				return domain.ConvertedTotal{}, fmt.Errorf("rate.Convert: %w", err)
+			}
+
+			converted.Rates = append(converted.Rates, rate)
+		}
+
+		total, err := converted.Total.Add(subtotal)
+		if err != nil {
+			return domain.ConvertedTotal{}, fmt.Errorf("Total.Add: %w", err)
+		}
+
+		converted.Total = total
+	}
+
+	converted.Total = converted.Total.Round()
+
+	return converted, nil
+}
+
+func cartToOrder(cart domain.Cart) domain.Order {
+	items := make([]domain.OrderItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, domain.OrderItem{
+			ProductID: item.ProductID,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return domain.Order{
+		OwnerID: cart.OwnerID,
+		Items:   items,
+	}
 }
@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	domain "github.com/nikolayk812/go-tests/internal/domain"
+	currency "golang.org/x/text/currency"
+
 	mock "github.com/stretchr/testify/mock"
 
 	uuid "github.com/google/uuid"
@@ -34,6 +36,98 @@ func (_m *MockCartService) AddItem(ctx context.Context, ownerID string, item dom
 	return r0
 }
 
+// AddItems provides a mock function with given fields: ctx, ownerID, items
+func (_m *MockCartService) AddItems(ctx context.Context, ownerID string, items []domain.CartItem) error {
+	ret := _m.Called(ctx, ownerID, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddItems")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.CartItem) error); ok {
+		r0 = rf(ctx, ownerID, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Checkout provides a mock function with given fields: ctx, ownerID
+func (_m *MockCartService) Checkout(ctx context.Context, ownerID string) (domain.Order, error) {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Checkout")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Order, error)); ok {
+		return rf(ctx, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Order); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClearCart provides a mock function with given fields: ctx, ownerID
+func (_m *MockCartService) ClearCart(ctx context.Context, ownerID string) error {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearCart")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConvertTotal provides a mock function with given fields: ctx, cart, to
+func (_m *MockCartService) ConvertTotal(ctx context.Context, cart domain.Cart, to currency.Unit) (domain.ConvertedTotal, error) {
+	ret := _m.Called(ctx, cart, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConvertTotal")
+	}
+
+	var r0 domain.ConvertedTotal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Cart, currency.Unit) (domain.ConvertedTotal, error)); ok {
+		return rf(ctx, cart, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Cart, currency.Unit) domain.ConvertedTotal); ok {
+		r0 = rf(ctx, cart, to)
+	} else {
+		r0 = ret.Get(0).(domain.ConvertedTotal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Cart, currency.Unit) error); ok {
+		r1 = rf(ctx, cart, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteItem provides a mock function with given fields: ctx, ownerID, productID
 func (_m *MockCartService) DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) error {
 	ret := _m.Called(ctx, ownerID, productID)
@@ -52,6 +146,34 @@ func (_m *MockCartService) DeleteItem(ctx context.Context, ownerID string, produ
 	return r0
 }
 
+// DeleteItems provides a mock function with given fields: ctx, ownerID, productIDs
+func (_m *MockCartService) DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	ret := _m.Called(ctx, ownerID, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItems")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID) (int, error)); ok {
+		return rf(ctx, ownerID, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID) int); ok {
+		r0 = rf(ctx, ownerID, productIDs)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []uuid.UUID) error); ok {
+		r1 = rf(ctx, ownerID, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCart provides a mock function with given fields: ctx, ownerID
 func (_m *MockCartService) GetCart(ctx context.Context, ownerID string) (domain.Cart, error) {
 	ret := _m.Called(ctx, ownerID)
@@ -80,6 +202,52 @@ func (_m *MockCartService) GetCart(ctx context.Context, ownerID string) (domain.
 	return r0, r1
 }
 
+// Reprice provides a mock function with given fields: ctx, ownerID, prices
+func (_m *MockCartService) Reprice(ctx context.Context, ownerID string, prices []domain.ItemPrice) (domain.Cart, error) {
+	ret := _m.Called(ctx, ownerID, prices)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reprice")
+	}
+
+	var r0 domain.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.ItemPrice) (domain.Cart, error)); ok {
+		return rf(ctx, ownerID, prices)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.ItemPrice) domain.Cart); ok {
+		r0 = rf(ctx, ownerID, prices)
+	} else {
+		r0 = ret.Get(0).(domain.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []domain.ItemPrice) error); ok {
+		r1 = rf(ctx, ownerID, prices)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetItemQuantity provides a mock function with given fields: ctx, ownerID, productID, quantity
+func (_m *MockCartService) SetItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) error {
+	ret := _m.Called(ctx, ownerID, productID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetItemQuantity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, int) error); ok {
+		r0 = rf(ctx, ownerID, productID, quantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockCartService creates a new instance of MockCartService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockCartService(t interface {
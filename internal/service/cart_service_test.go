@@ -1,41 +1,69 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"github.com/brianvoe/gofakeit"
 	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/nikolayk812/go-tests/internal/repository/repotest"
 	"github.com/shopspring/decimal"
 	"golang.org/x/text/currency"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nikolayk812/go-tests/internal/domain"
 	"github.com/nikolayk812/go-tests/internal/port"
 	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestCartService_AddItem(t *testing.T) {
 	item1 := fakeCartItem()
+	product1 := productOf(item1)
 
 	item2 := fakeCartItem()
 	item2.ProductID = uuid.Nil
 
+	item3 := fakeCartItem()
+	item3.Quantity = 0
+	product3 := productOf(item3)
+
+	item3Single := item3
+	item3Single.Quantity = 1
+
+	item4 := fakeCartItem()
+	item4.Quantity = -1
+
+	// price is omitted by the client
+	item5 := fakeCartItem()
+	product5 := productOf(item5)
+	item5.Price = domain.Money{}
+
+	item5Priced := item5
+	item5Priced.Price = product5.Price
+
+	item6 := fakeCartItem()
+	product6 := productOf(item6)
+	product6.Price.Amount = product6.Price.Amount.Add(decimal.NewFromInt(1))
+
 	okOwnerID := gofakeit.UUID()
 
 	tests := []struct {
 		name      string
 		item      domain.CartItem
 		ownerID   string
-		mockSetup func(repo *port.MockCartRepository)
+		mockSetup func(repo *port.MockRepository)
 		wantErr   error
 	}{
 		{
 			name:    "success",
 			item:    item1,
 			ownerID: okOwnerID,
-			mockSetup: func(repo *port.MockCartRepository) {
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item1.ProductID).Return(product1, nil)
 				repo.On("AddItem", mock.Anything, okOwnerID, item1).
 					Return(nil)
 			},
@@ -44,19 +72,65 @@ func TestCartService_AddItem(t *testing.T) {
 			name:    "ownerID is empty",
 			item:    item1,
 			ownerID: "",
-			wantErr: errors.New("ownerID is empty"),
+			wantErr: errors.New("owner_id is empty"),
 		},
 		{
 			name:    "productID is empty",
 			item:    item2,
 			ownerID: okOwnerID,
-			wantErr: errors.New("productID is empty"),
+			wantErr: errors.New("product_id is empty"),
+		},
+		{
+			name:    "quantity defaults to one",
+			item:    item3,
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item3.ProductID).Return(product3, nil)
+				repo.On("AddItem", mock.Anything, okOwnerID, item3Single).
+					Return(nil)
+			},
+		},
+		{
+			name:    "quantity is negative",
+			item:    item4,
+			ownerID: okOwnerID,
+			wantErr: errors.New("quantity is negative"),
+		},
+		{
+			name:    "price omitted: catalog price used",
+			item:    item5,
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item5.ProductID).Return(product5, nil)
+				repo.On("AddItem", mock.Anything, okOwnerID, item5Priced).
+					Return(nil)
+			},
+		},
+		{
+			name:    "price mismatch",
+			item:    item6,
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item6.ProductID).Return(product6, nil)
+			},
+			wantErr: service.ErrPriceMismatch,
+		},
+		{
+			name:    "product not found",
+			item:    item1,
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item1.ProductID).
+					Return(domain.Product{}, repository.ErrProductNotFound)
+			},
+			wantErr: service.ErrProductNotFound,
 		},
 		{
 			name:    "duplicate item",
 			item:    item1,
 			ownerID: okOwnerID,
-			mockSetup: func(repo *port.MockCartRepository) {
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item1.ProductID).Return(product1, nil)
 				repo.On("AddItem", mock.Anything, okOwnerID, item1).
 					Return(repository.ErrCartDuplicateItem)
 			},
@@ -66,7 +140,8 @@ func TestCartService_AddItem(t *testing.T) {
 			name:    "unexpected error from repo",
 			item:    item1,
 			ownerID: okOwnerID,
-			mockSetup: func(repo *port.MockCartRepository) {
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item1.ProductID).Return(product1, nil)
 				repo.On("AddItem", mock.Anything, okOwnerID, item1).
 					Return(errors.New("unexpected error"))
 			},
@@ -76,40 +151,779 @@ func TestCartService_AddItem(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := new(port.MockCartRepository)
+			mockRepo := new(port.MockRepository)
 
-			cs, err := service.NewCart(mockRepo)
+			cs, err := service.NewCart(new(port.MockCartRepository), txUnitOfWork(mockRepo), new(port.MockExchangeRateProvider),
+				new(port.MockProductCatalog))
 			require.NoError(t, err)
 
 			if tt.mockSetup != nil {
 				tt.mockSetup(mockRepo)
 			}
 
+			// every change locks the cart first
+			mockRepo.On("LockCart", mock.Anything, mock.Anything).Return(int64(1), nil).Maybe()
+
 			err = cs.AddItem(t.Context(), tt.ownerID, tt.item)
 			if tt.wantErr != nil {
-				require.EqualError(t, err, tt.wantErr.Error())
+				require.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartService_AddItems(t *testing.T) {
+	item1 := fakeCartItem()
+	product1 := productOf(item1)
+
+	item2 := fakeCartItem()
+	product2 := productOf(item2)
+
+	item3 := fakeCartItem()
+
+	invalidItem1 := fakeCartItem()
+	invalidItem1.ProductID = uuid.Nil
+	invalidItem1.Quantity = -1
+
+	invalidItem2 := fakeCartItem()
+	invalidItem2.Price = domain.NewMoney(decimal.Zero, currency.EUR)
+
+	invalidItem3 := fakeCartItem()
+	invalidItem3.Price = domain.NewMoney(decimal.RequireFromString("1.005"), currency.EUR)
+
+	// an unknown currency code is mapped to UnsupportedCurrency by the rest mapper
+	invalidItem4 := fakeCartItem()
+	invalidItem4.Price = domain.NewMoney(decimal.NewFromInt(1), service.UnsupportedCurrency)
+
+	okOwnerID := gofakeit.UUID()
+
+	tests := []struct {
+		name           string
+		items          []domain.CartItem
+		ownerID        string
+		mockSetup      func(repo *port.MockRepository)
+		wantItemErrs   []service.ItemError
+		wantViolations []service.Violation
+		wantErr        error
+	}{
+		{
+			name:    "success",
+			items:   []domain.CartItem{item1, item2},
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item1.ProductID).Return(product1, nil)
+				repo.On("GetProduct", mock.Anything, item2.ProductID).Return(product2, nil)
+				repo.On("AddItem", mock.Anything, okOwnerID, item1).Return(nil)
+				repo.On("AddItem", mock.Anything, okOwnerID, item2).Return(nil)
+			},
+		},
+		{
+			name:    "ownerID is empty",
+			items:   []domain.CartItem{item1},
+			wantErr: errors.New("owner_id is empty"),
+		},
+		{
+			name:    "items are empty",
+			ownerID: okOwnerID,
+			wantErr: errors.New("items is empty"),
+		},
+		{
+			name:    "invalid fields are all reported",
+			items:   []domain.CartItem{item1, invalidItem1, invalidItem2, invalidItem3, invalidItem4},
+			ownerID: "owner 1",
+			wantViolations: []service.Violation{
+				{Field: "owner_id", Reason: "must be up to 255 letters, digits, '.', '_', '@' or '-'"},
+				{Field: "items[1].product_id", Reason: "is empty"},
+				{Field: "items[1].quantity", Reason: "is negative"},
+				{Field: "items[2].price.amount", Reason: "must be positive"},
+				{Field: "items[3].price.amount", Reason: "has more than 2 decimal places for EUR"},
+				{Field: "items[4].price.currency", Reason: "is not a supported currency"},
+			},
+		},
+		{
+			name:    "rejected items are reported",
+			items:   []domain.CartItem{item1, item2, item3},
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item1.ProductID).Return(product1, nil)
+				repo.On("GetProduct", mock.Anything, item2.ProductID).Return(product2, nil)
+				repo.On("GetProduct", mock.Anything, item3.ProductID).
+					Return(domain.Product{}, repository.ErrProductNotFound)
+				repo.On("AddItem", mock.Anything, okOwnerID, item1).Return(nil)
+				repo.On("AddItem", mock.Anything, okOwnerID, item2).Return(repository.ErrCartDuplicateItem)
+			},
+			wantItemErrs: []service.ItemError{
+				{Index: 1, ProductID: item2.ProductID, Err: service.ErrCartDuplicateItem},
+				{Index: 2, ProductID: item3.ProductID, Err: service.ErrProductNotFound},
+			},
+		},
+		{
+			name:    "unexpected error from repo",
+			items:   []domain.CartItem{item1, item2},
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetProduct", mock.Anything, item1.ProductID).Return(product1, nil)
+				repo.On("AddItem", mock.Anything, okOwnerID, item1).Return(errors.New("unexpected error"))
+			},
+			wantErr: errors.New("uow.WithTx: cs.addItem[0]: repo.AddItem: unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(port.MockRepository)
+
+			cs, err := service.NewCart(new(port.MockCartRepository), txUnitOfWork(mockRepo), new(port.MockExchangeRateProvider),
+				new(port.MockProductCatalog))
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			// every change locks the cart first
+			mockRepo.On("LockCart", mock.Anything, mock.Anything).Return(int64(1), nil).Maybe()
+
+			err = cs.AddItems(t.Context(), tt.ownerID, tt.items)
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			case tt.wantViolations != nil:
+				var validationErr *service.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantViolations, validationErr.Violations)
+				return
+			case tt.wantItemErrs != nil:
+				var batchErr *service.BatchError
+				require.ErrorAs(t, err, &batchErr)
+				require.Len(t, batchErr.Items, len(tt.wantItemErrs))
+
+				for i, want := range tt.wantItemErrs {
+					actual := batchErr.Items[i]
+					assert.Equal(t, want.Index, actual.Index)
+					assert.Equal(t, want.ProductID, actual.ProductID)
+					assert.ErrorIs(t, actual.Err, want.Err)
+				}
+			default:
+				require.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartService_DeleteItems(t *testing.T) {
+	ownerID := gofakeit.UUID()
+	productIDs := []uuid.UUID{uuid.MustParse(gofakeit.UUID()), uuid.MustParse(gofakeit.UUID())}
+
+	mockRepo := new(port.MockRepository)
+	mockRepo.On("LockCart", mock.Anything, ownerID).Return(int64(1), nil)
+	mockRepo.On("DeleteItems", mock.Anything, ownerID, productIDs).Return(1, nil)
+
+	cs, err := service.NewCart(new(port.MockCartRepository), txUnitOfWork(mockRepo), new(port.MockExchangeRateProvider), new(port.MockProductCatalog))
+	require.NoError(t, err)
+
+	deleted, err := cs.DeleteItems(t.Context(), ownerID, productIDs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = cs.DeleteItems(t.Context(), ownerID, nil)
+	require.ErrorContains(t, err, "product_ids is empty")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCartService_ClearCart(t *testing.T) {
+	ownerID := gofakeit.UUID()
+
+	mockRepo := new(port.MockRepository)
+	mockRepo.On("LockCart", mock.Anything, ownerID).Return(int64(1), nil)
+	mockRepo.On("ClearCart", mock.Anything, ownerID).Return(nil)
+
+	cs, err := service.NewCart(new(port.MockCartRepository), txUnitOfWork(mockRepo), new(port.MockExchangeRateProvider), new(port.MockProductCatalog))
+	require.NoError(t, err)
+
+	err = cs.ClearCart(t.Context(), ownerID)
+	require.NoError(t, err)
+
+	err = cs.ClearCart(t.Context(), "")
+	require.ErrorContains(t, err, "owner_id is empty")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCartService_SetItemQuantity(t *testing.T) {
+	productID := uuid.MustParse(gofakeit.UUID())
+
+	okOwnerID := gofakeit.UUID()
+
+	tests := []struct {
+		name      string
+		ownerID   string
+		productID uuid.UUID
+		quantity  int
+		mockSetup func(repo *port.MockRepository)
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			ownerID:   okOwnerID,
+			productID: productID,
+			quantity:  3,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("UpdateItemQuantity", mock.Anything, okOwnerID, productID, 3).
+					Return(true, nil)
+			},
+		},
+		{
+			name:      "zero quantity removes item",
+			ownerID:   okOwnerID,
+			productID: productID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("DeleteItem", mock.Anything, okOwnerID, productID).
+					Return(true, nil)
+			},
+		},
+		{
+			name:      "item not found",
+			ownerID:   okOwnerID,
+			productID: productID,
+			quantity:  3,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("UpdateItemQuantity", mock.Anything, okOwnerID, productID, 3).
+					Return(false, nil)
+			},
+			wantErr: service.ErrCartItemNotFound,
+		},
+		{
+			name:      "quantity is negative",
+			ownerID:   okOwnerID,
+			productID: productID,
+			quantity:  -1,
+			wantErr:   errors.New("invalid request: quantity is negative"),
+		},
+		{
+			name:      "productID is empty",
+			ownerID:   okOwnerID,
+			productID: uuid.Nil,
+			quantity:  3,
+			wantErr:   errors.New("invalid request: product_id is empty"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(port.MockRepository)
+
+			cs, err := service.NewCart(new(port.MockCartRepository), txUnitOfWork(mockRepo), new(port.MockExchangeRateProvider), new(port.MockProductCatalog))
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			// every change locks the cart first
+			mockRepo.On("LockCart", mock.Anything, mock.Anything).Return(int64(1), nil).Maybe()
+
+			err = cs.SetItemQuantity(t.Context(), tt.ownerID, tt.productID, tt.quantity)
+			if tt.wantErr != nil {
+				require.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartService_Checkout(t *testing.T) {
+	item1 := fakeCartItem()
+	item2 := fakeCartItem()
+
+	okOwnerID := gofakeit.UUID()
+	orderID := uuid.MustParse(gofakeit.UUID())
+
+	// newCart returns a fresh cart per call, as price changes are flagged on the items in place
+	newCart := func() domain.Cart {
+		return domain.Cart{
+			OwnerID: okOwnerID,
+			Items:   []domain.CartItem{item1, item2},
+		}
+	}
+
+	productIDs := []uuid.UUID{item1.ProductID, item2.ProductID}
+	products := []domain.Product{productOf(item1), productOf(item2)}
+
+	changedProduct2 := productOf(item2)
+	changedProduct2.Price.Amount = changedProduct2.Price.Amount.Add(decimal.NewFromInt(1))
+
+	expectedOrder := domain.Order{
+		ID:      orderID,
+		OwnerID: okOwnerID,
+		Items: []domain.OrderItem{
+			{ProductID: item1.ProductID, Price: item1.Price, Quantity: item1.Quantity},
+			{ProductID: item2.ProductID, Price: item2.Price, Quantity: item2.Quantity},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ownerID   string
+		mockSetup func(repo *port.MockRepository)
+		wantOrder domain.Order
+		wantErr   error
+	}{
+		{
+			name:    "success",
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("LockCart", mock.Anything, okOwnerID).Return(int64(1), nil)
+				repo.On("GetCart", mock.Anything, okOwnerID).Return(newCart(), nil)
+				repo.On("GetProducts", mock.Anything, productIDs).Return(products, nil)
+				repo.On("CreateOrder", mock.Anything, domain.Order{
+					OwnerID: okOwnerID,
+					Items:   expectedOrder.Items,
+				}).Return(orderID, nil)
+				repo.On("DeleteItem", mock.Anything, okOwnerID, item1.ProductID).Return(true, nil)
+				repo.On("DeleteItem", mock.Anything, okOwnerID, item2.ProductID).Return(true, nil)
+				repo.On("GetOrder", mock.Anything, orderID).Return(expectedOrder, nil)
+			},
+			wantOrder: expectedOrder,
+		},
+		{
+			name:    "ownerID is empty",
+			ownerID: "",
+			wantErr: errors.New("owner_id is empty"),
+		},
+		{
+			name:    "empty cart",
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetCart", mock.Anything, okOwnerID).
+					Return(domain.Cart{OwnerID: okOwnerID}, nil)
+			},
+			wantErr: service.ErrCartEmpty,
+		},
+		{
+			name:    "prices changed",
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetCart", mock.Anything, okOwnerID).Return(newCart(), nil)
+				repo.On("GetProducts", mock.Anything, productIDs).
+					Return([]domain.Product{products[0], changedProduct2}, nil)
+			},
+			wantErr: service.ErrCartPricesChanged,
+		},
+		{
+			name:    "product removed from the catalog",
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetCart", mock.Anything, okOwnerID).Return(newCart(), nil)
+				repo.On("GetProducts", mock.Anything, productIDs).Return(products[:1], nil)
+			},
+			wantErr: service.ErrProductNotFound,
+		},
+		{
+			name:    "create order error",
+			ownerID: okOwnerID,
+			mockSetup: func(repo *port.MockRepository) {
+				repo.On("GetCart", mock.Anything, okOwnerID).Return(newCart(), nil)
+				repo.On("GetProducts", mock.Anything, productIDs).Return(products, nil)
+				repo.On("CreateOrder", mock.Anything, mock.Anything).
+					Return(uuid.Nil, errors.New("unexpected error"))
+			},
+			wantErr: errors.New("uow.WithTx: repo.CreateOrder: unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(port.MockRepository)
+
+			// the catalog is read through the transaction, the pool-bound catalog is not called
+			cs, err := service.NewCart(new(port.MockCartRepository), txUnitOfWork(mockRepo), new(port.MockExchangeRateProvider),
+				new(port.MockProductCatalog))
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			// every change locks the cart first
+			mockRepo.On("LockCart", mock.Anything, mock.Anything).Return(int64(1), nil).Maybe()
+
+			order, err := cs.Checkout(t.Context(), tt.ownerID)
+			if tt.wantErr != nil {
+				require.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOrder, order)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartService_GetCart(t *testing.T) {
+	item1 := fakeCartItem()
+	item2 := fakeCartItem()
+
+	ownerID := gofakeit.UUID()
+
+	cart := domain.Cart{
+		OwnerID: ownerID,
+		Items:   []domain.CartItem{item1, item2},
+	}
+
+	product2 := productOf(item2)
+	product2.Price.Amount = product2.Price.Amount.Add(decimal.NewFromInt(1))
+
+	flaggedItem2 := item2
+	flaggedItem2.PriceChange = &domain.PriceChange{
+		OldPrice:  item2.Price,
+		NewPrice:  product2.Price,
+		ChangedAt: product2.UpdatedAt,
+	}
+
+	mockRepo := new(port.MockCartRepository)
+	mockCatalog := new(port.MockProductCatalog)
+
+	mockRepo.On("GetCart", mock.Anything, ownerID).Return(cart, nil)
+	mockCatalog.On("GetProducts", mock.Anything, []uuid.UUID{item1.ProductID, item2.ProductID}).
+		Return([]domain.Product{productOf(item1), product2}, nil)
+
+	cs, err := service.NewCart(mockRepo, new(port.MockUnitOfWork), new(port.MockExchangeRateProvider), mockCatalog)
+	require.NoError(t, err)
+
+	actual, err := cs.GetCart(t.Context(), ownerID)
+	require.NoError(t, err)
+
+	expected := domain.Cart{
+		OwnerID: ownerID,
+		Items:   []domain.CartItem{item1, flaggedItem2},
+	}
+	require.Equal(t, expected, actual)
+
+	mockRepo.AssertExpectations(t)
+	mockCatalog.AssertExpectations(t)
+}
+
+func TestCartService_Reprice(t *testing.T) {
+	item := fakeCartItem()
+	ownerID := gofakeit.UUID()
+
+	product := productOf(item)
+	product.Price.Amount = product.Price.Amount.Add(decimal.NewFromInt(1))
+
+	accepted := []domain.ItemPrice{{ProductID: item.ProductID, Price: product.Price}}
+
+	staleAccepted := []domain.ItemPrice{{ProductID: item.ProductID, Price: item.Price}}
+
+	tests := []struct {
+		name      string
+		prices    []domain.ItemPrice
+		mockSetup func(repo *port.MockCartRepository, txRepo *port.MockRepository, catalog *port.MockProductCatalog)
+		wantErr   error
+	}{
+		{
+			name:   "success",
+			prices: accepted,
+			mockSetup: func(repo *port.MockCartRepository, txRepo *port.MockRepository, catalog *port.MockProductCatalog) {
+				txRepo.On("GetProduct", mock.Anything, item.ProductID).Return(product, nil)
+				txRepo.On("UpdateItemPrice", mock.Anything, ownerID, item.ProductID, product.Price).Return(true, nil)
+
+				repricedItem := item
+				repricedItem.Price = product.Price
+				repo.On("GetCart", mock.Anything, ownerID).
+					Return(domain.Cart{OwnerID: ownerID, Items: []domain.CartItem{repricedItem}}, nil)
+				catalog.On("GetProducts", mock.Anything, []uuid.UUID{item.ProductID}).
+					Return([]domain.Product{product}, nil)
+			},
+		},
+		{
+			name:    "prices are empty",
+			wantErr: errors.New("items is empty"),
+		},
+		{
+			name:   "accepted price is not the catalog price",
+			prices: staleAccepted,
+			mockSetup: func(repo *port.MockCartRepository, txRepo *port.MockRepository, catalog *port.MockProductCatalog) {
+				txRepo.On("GetProduct", mock.Anything, item.ProductID).Return(product, nil)
+			},
+			wantErr: service.ErrPriceMismatch,
+		},
+		{
+			name:   "item not in cart",
+			prices: accepted,
+			mockSetup: func(repo *port.MockCartRepository, txRepo *port.MockRepository, catalog *port.MockProductCatalog) {
+				txRepo.On("GetProduct", mock.Anything, item.ProductID).Return(product, nil)
+				txRepo.On("UpdateItemPrice", mock.Anything, ownerID, item.ProductID, product.Price).Return(false, nil)
+			},
+			wantErr: service.ErrCartItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(port.MockCartRepository)
+			mockTxRepo := new(port.MockRepository)
+			mockCatalog := new(port.MockProductCatalog)
+
+			cs, err := service.NewCart(mockRepo, txUnitOfWork(mockTxRepo), new(port.MockExchangeRateProvider), mockCatalog)
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo, mockTxRepo, mockCatalog)
+			}
+
+			mockTxRepo.On("LockCart", mock.Anything, ownerID).Return(int64(1), nil).Maybe()
+
+			cart, err := cs.Reprice(t.Context(), ownerID, tt.prices)
+			if tt.wantErr != nil {
+				require.ErrorContains(t, err, tt.wantErr.Error())
 				return
 			}
 
 			require.NoError(t, err)
+			require.False(t, cart.HasPriceChanges())
 
 			mockRepo.AssertExpectations(t)
+			mockTxRepo.AssertExpectations(t)
+			mockCatalog.AssertExpectations(t)
 		})
 	}
 }
 
+func TestCartService_ConditionalChange(t *testing.T) {
+	ownerID := gofakeit.UUID()
+	productID := uuid.MustParse(gofakeit.UUID())
+
+	tests := []struct {
+		name            string
+		versions        []int64 // nil for an unconditional change
+		mockSetup       func(txRepo *port.MockRepository)
+		wantErr         error
+		wantErrContains string
+	}{
+		{
+			name: "unconditional change",
+			mockSetup: func(txRepo *port.MockRepository) {
+				txRepo.On("LockCart", mock.Anything, ownerID).Return(int64(3), nil)
+				txRepo.On("DeleteItem", mock.Anything, ownerID, productID).Return(true, nil)
+			},
+		},
+		{
+			name:     "expected version",
+			versions: []int64{2, 3},
+			mockSetup: func(txRepo *port.MockRepository) {
+				txRepo.On("LockCart", mock.Anything, ownerID).Return(int64(3), nil)
+				txRepo.On("DeleteItem", mock.Anything, ownerID, productID).Return(true, nil)
+			},
+		},
+		{
+			name:     "cart changed",
+			versions: []int64{2},
+			mockSetup: func(txRepo *port.MockRepository) {
+				txRepo.On("LockCart", mock.Anything, ownerID).Return(int64(3), nil)
+			},
+			wantErr:         service.ErrCartVersionMismatch,
+			wantErrContains: "version 3",
+		},
+		{
+			name:     "no version matches",
+			versions: []int64{},
+			mockSetup: func(txRepo *port.MockRepository) {
+				txRepo.On("LockCart", mock.Anything, ownerID).Return(int64(0), nil)
+			},
+			wantErr: service.ErrCartVersionMismatch,
+		},
+		{
+			name:     "item not found",
+			versions: []int64{3},
+			mockSetup: func(txRepo *port.MockRepository) {
+				txRepo.On("LockCart", mock.Anything, ownerID).Return(int64(3), nil)
+				txRepo.On("DeleteItem", mock.Anything, ownerID, productID).Return(false, nil)
+			},
+			wantErr: service.ErrCartItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTxRepo := new(port.MockRepository)
+			tt.mockSetup(mockTxRepo)
+
+			cs, err := service.NewCart(new(port.MockCartRepository), txUnitOfWork(mockTxRepo), new(port.MockExchangeRateProvider), new(port.MockProductCatalog))
+			require.NoError(t, err)
+
+			ctx := t.Context()
+			if tt.versions != nil {
+				ctx = service.WithExpectedVersions(ctx, tt.versions)
+			}
+
+			err = cs.DeleteItem(ctx, ownerID, productID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorContains(t, err, tt.wantErrContains)
+			} else {
+				require.NoError(t, err)
+			}
+
+			mockTxRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// txUnitOfWork returns a unit of work which runs the transaction function against the given repository.
+func txUnitOfWork(txRepo port.Repository) *port.MockUnitOfWork {
+	uow := new(port.MockUnitOfWork)
+
+	uow.On("WithTx", mock.Anything, mock.Anything).Maybe().
+		Return(func(_ context.Context, fn func(port.Repository) error) error {
+			return fn(txRepo)
+		})
+
+	return uow
+}
+
+func TestCartService_ConvertTotal(t *testing.T) {
+	updatedAt := time.Now()
+
+	usdToEUR := domain.ExchangeRate{
+		From:      currency.USD,
+		To:        currency.EUR,
+		Rate:      decimal.RequireFromString("0.9"),
+		UpdatedAt: updatedAt,
+	}
+
+	eurItem := domain.CartItem{
+		ProductID: uuid.MustParse(gofakeit.UUID()),
+		Price:     domain.NewMoney(decimal.RequireFromString("10.00"), currency.EUR),
+		Quantity:  2,
+	}
+
+	usdItem := domain.CartItem{
+		ProductID: uuid.MustParse(gofakeit.UUID()),
+		Price:     domain.NewMoney(decimal.RequireFromString("3.33"), currency.USD),
+		Quantity:  1,
+	}
+
+	tests := []struct {
+		name      string
+		items     []domain.CartItem
+		mockSetup func(rates *port.MockExchangeRateProvider)
+		wantTotal domain.ConvertedTotal
+		wantErr   error
+	}{
+		{
+			name: "empty cart",
+			wantTotal: domain.ConvertedTotal{
+				Total: domain.NewMoney(decimal.Zero, currency.EUR),
+				Rates: []domain.ExchangeRate{},
+			},
+		},
+		{
+			name:  "same currency",
+			items: []domain.CartItem{eurItem},
+			wantTotal: domain.ConvertedTotal{
+				Total: domain.NewMoney(decimal.RequireFromString("20"), currency.EUR),
+				Rates: []domain.ExchangeRate{},
+			},
+		},
+		{
+			name:  "mixed currencies",
+			items: []domain.CartItem{eurItem, usdItem},
+			mockSetup: func(rates *port.MockExchangeRateProvider) {
+				rates.On("GetRate", mock.Anything, currency.USD, currency.EUR).Return(usdToEUR, nil)
+			},
+			wantTotal: domain.ConvertedTotal{
+				// 20 + 3.33 * 0.9 = 22.997
+				Total: domain.NewMoney(decimal.RequireFromString("23"), currency.EUR),
+				Rates: []domain.ExchangeRate{usdToEUR},
+			},
+		},
+		{
+			name:  "rate not found",
+			items: []domain.CartItem{eurItem, usdItem},
+			mockSetup: func(rates *port.MockExchangeRateProvider) {
+				rates.On("GetRate", mock.Anything, currency.USD, currency.EUR).
+					Return(domain.ExchangeRate{}, repository.ErrExchangeRateNotFound)
+			},
+			wantErr: service.ErrExchangeRateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRates := new(port.MockExchangeRateProvider)
+
+			cs, err := service.NewCart(new(port.MockCartRepository), new(port.MockUnitOfWork), mockRates, new(port.MockProductCatalog))
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRates)
+			}
+
+			cart := domain.Cart{OwnerID: gofakeit.UUID(), Items: tt.items}
+
+			total, err := cs.ConvertTotal(t.Context(), cart, currency.EUR)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.wantTotal.Total.Equal(total.Total), "want %s, got %s", tt.wantTotal.Total, total.Total)
+			assert.Equal(t, tt.wantTotal.Rates, total.Rates)
+
+			mockRates.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartService_ConvertTotal_UnsupportedCurrency(t *testing.T) {
+	cs, err := service.NewCart(new(port.MockCartRepository), new(port.MockUnitOfWork), new(port.MockExchangeRateProvider),
+		new(port.MockProductCatalog))
+	require.NoError(t, err)
+
+	_, err = cs.ConvertTotal(t.Context(), domain.Cart{OwnerID: gofakeit.UUID()}, service.UnsupportedCurrency)
+
+	var validationErr *service.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []service.Violation{{Field: "currency", Reason: "is not a supported currency"}}, validationErr.Violations)
+}
+
+func productOf(item domain.CartItem) domain.Product {
+	return domain.Product{
+		ID:        item.ProductID,
+		Name:      gofakeit.Word(),
+		Price:     item.Price,
+		UpdatedAt: time.Now(),
+	}
+}
+
 func fakeCartItem() domain.CartItem {
 	productID := uuid.MustParse(gofakeit.UUID())
 
 	price := gofakeit.Price(1, 100)
 
-	currencyUnit := currency.MustParseISO(gofakeit.CurrencyShort())
+	currencyUnit := repotest.FakeCurrency()
 
 	return domain.CartItem{
 		ProductID: productID,
-		Price: domain.Money{
-			Amount:   decimal.NewFromFloat(price),
-			Currency: currencyUnit,
-		},
+		// valid prices have no more decimal places than the currency minor units
+		Price:    domain.NewMoney(decimal.NewFromFloat(price), currencyUnit).Round(),
+		Quantity: gofakeit.Number(1, 5),
 	}
 }
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/currency"
+)
+
+const tracerName = "github.com/nikolayk812/go-tests/internal/service"
+
+// cartTracing records a span for every operation of the wrapped cart service.
+type cartTracing struct {
+	next   CartService
+	tracer trace.Tracer
+}
+
+// NewCartTracing wraps the cart service with "CartService.<Method>" spans created by the tracer provider.
+// Spans carry the cart.outcome attribute, only unexpected errors set the error status.
+func NewCartTracing(next CartService, tp trace.TracerProvider) (CartService, error) {
+	if next == nil {
+		return nil, errors.New("next is nil")
+	}
+
+	if tp == nil {
+		return nil, errors.New("tp is nil")
+	}
+
+	return &cartTracing{next: next, tracer: tp.Tracer(tracerName)}, nil
+}
+
+func (t *cartTracing) GetCart(ctx context.Context, ownerID string) (domain.Cart, error) {
+	ctx, span := t.start(ctx, "GetCart", ownerID)
+	cart, err := t.next.GetCart(ctx, ownerID)
+	endSpan(span, err)
+
+	return cart, err
+}
+
+func (t *cartTracing) AddItem(ctx context.Context, ownerID string, item domain.CartItem) error {
+	ctx, span := t.start(ctx, "AddItem", ownerID)
+	err := t.next.AddItem(ctx, ownerID, item)
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *cartTracing) AddItems(ctx context.Context, ownerID string, items []domain.CartItem) error {
+	ctx, span := t.start(ctx, "AddItems", ownerID, attribute.Int("cart.items", len(items)))
+	err := t.next.AddItems(ctx, ownerID, items)
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *cartTracing) DeleteItem(ctx context.Context, ownerID string, productID uuid.UUID) error {
+	ctx, span := t.start(ctx, "DeleteItem", ownerID)
+	err := t.next.DeleteItem(ctx, ownerID, productID)
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *cartTracing) DeleteItems(ctx context.Context, ownerID string, productIDs []uuid.UUID) (int, error) {
+	ctx, span := t.start(ctx, "DeleteItems", ownerID, attribute.Int("cart.items", len(productIDs)))
+	deleted, err := t.next.DeleteItems(ctx, ownerID, productIDs)
+	endSpan(span, err)
+
+	return deleted, err
+}
+
+func (t *cartTracing) ClearCart(ctx context.Context, ownerID string) error {
+	ctx, span := t.start(ctx, "ClearCart", ownerID)
+	err := t.next.ClearCart(ctx, ownerID)
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *cartTracing) SetItemQuantity(ctx context.Context, ownerID string, productID uuid.UUID, quantity int) error {
+	ctx, span := t.start(ctx, "SetItemQuantity", ownerID)
+	err := t.next.SetItemQuantity(ctx, ownerID, productID, quantity)
+	endSpan(span, err)
+
+	return err
+}
+
+func (t *cartTracing) Checkout(ctx context.Context, ownerID string) (domain.Order, error) {
+	ctx, span := t.start(ctx, "Checkout", ownerID)
+	order, err := t.next.Checkout(ctx, ownerID)
+	endSpan(span, err)
+
+	return order, err
+}
+
+func (t *cartTracing) ConvertTotal(ctx context.Context, cart domain.Cart, to currency.Unit) (domain.ConvertedTotal, error) {
+	ctx, span := t.start(ctx, "ConvertTotal", cart.OwnerID)
+	total, err := t.next.ConvertTotal(ctx, cart, to)
+	endSpan(span, err)
+
+	return total, err
+}
+
+func (t *cartTracing) Reprice(ctx context.Context, ownerID string, prices []domain.ItemPrice) (domain.Cart, error) {
+	ctx, span := t.start(ctx, "Reprice", ownerID, attribute.Int("cart.items", len(prices)))
+	cart, err := t.next.Reprice(ctx, ownerID, prices)
+	endSpan(span, err)
+
+	return cart, err
+}
+
+func (t *cartTracing) start(ctx context.Context, method, ownerID string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("cart.owner_id", ownerID))
+
+	return t.tracer.Start(ctx, "CartService."+method, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	outcome := outcomeOf(err)
+	span.SetAttributes(attribute.String("cart.outcome", outcome))
+
+	// rejected and invalid requests are expected, they are not errors of the service
+	if outcome == OutcomeError {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestCartTracing(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantOutcome string
+		wantStatus  codes.Code
+	}{
+		{name: "success", wantOutcome: service.OutcomeSuccess},
+		{name: "rejected", err: service.ErrPriceMismatch, wantOutcome: service.OutcomeRejected},
+		{
+			name:        "unexpected error",
+			err:         errors.New("repo.AddItem: unexpected error"),
+			wantOutcome: service.OutcomeError,
+			wantStatus:  codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			var serviceSpan trace.SpanContext
+
+			mockService := new(service.MockCartService)
+			mockService.On("AddItem", mock.Anything, "owner1", mock.Anything).
+				Run(func(args mock.Arguments) {
+					serviceSpan = trace.SpanContextFromContext(args.Get(0).(context.Context))
+				}).
+				Return(tt.err)
+
+			cs, err := service.NewCartTracing(mockService, tp)
+			require.NoError(t, err)
+
+			err = cs.AddItem(t.Context(), "owner1", fakeCartItem())
+			assert.Equal(t, tt.err, err)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+			assert.Equal(t, "CartService.AddItem", span.Name())
+			assert.Equal(t, tt.wantStatus, span.Status().Code)
+
+			// the wrapped service runs within the span, so that the query spans are its children
+			assert.Equal(t, span.SpanContext(), serviceSpan)
+
+			attrs := attribute.NewSet(span.Attributes()...)
+
+			outcome, _ := attrs.Value("cart.outcome")
+			assert.Equal(t, tt.wantOutcome, outcome.AsString())
+
+			ownerID, _ := attrs.Value("cart.owner_id")
+			assert.Equal(t, "owner1", ownerID.AsString())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
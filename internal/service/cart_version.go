@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"slices"
+)
+
+type expectedVersionsKey struct{}
+
+// WithExpectedVersions makes the cart changes run with ctx conditional, e.g. on the If-Match header of the request.
+// They fail with ErrCartVersionMismatch unless the cart version is one of versions, so none match if it is empty.
+func WithExpectedVersions(ctx context.Context, versions []int64) context.Context {
+	return context.WithValue(ctx, expectedVersionsKey{}, versions)
+}
+
+// ExpectedVersionsFrom returns the versions set by WithExpectedVersions, ok is false for an unconditional change.
+func ExpectedVersionsFrom(ctx context.Context) ([]int64, bool) {
+	versions, ok := ctx.Value(expectedVersionsKey{}).([]int64)
+	return versions, ok
+}
+
+// change runs fn in a transaction which locks the cart first, so that the cart changes are serialized
+// and always lock the cart before its items, e.g. a checkout does not miss an item added concurrently.
+func (cs *cartService) change(ctx context.Context, ownerID string, fn func(repo port.Repository) error) error {
+	err := cs.uow.WithTx(ctx, func(repo port.Repository) error {
+		version, err := repo.LockCart(ctx, ownerID)
+		if err != nil {
+			return fmt.Errorf("repo.LockCart: %w", err)
+		}
+
+		if err := checkVersion(ctx, version); err != nil {
+			return err
+		}
+
+		return fn(repo)
+	})
+	if err != nil {
+		return fmt.Errorf("uow.WithTx: %w", err)
+	}
+
+	return nil
+}
+
+// checkVersion fails a conditional change if the locked cart version is not expected.
+func checkVersion(ctx context.Context, version int64) error {
+	expected, ok := ExpectedVersionsFrom(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !slices.Contains(expected, version) {
+		return fmt.Errorf("%w: version %d", ErrCartVersionMismatch, version)
+	}
+
+	return nil
+}
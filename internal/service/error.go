@@ -1,8 +1,69 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+)
 
 var (
 	ErrCartDuplicateItem = errors.New("duplicate cart item")
 	ErrCartItemNotFound  = errors.New("cart item not found")
+	ErrCartEmpty         = errors.New("cart is empty")
+	ErrOrderNotFound     = errors.New("order not found")
+
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+	ErrOrderStatusChanged      = errors.New("order status changed concurrently")
+
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+	ErrProductNotFound = errors.New("product not found")
+	ErrPriceMismatch   = errors.New("price does not match the catalog price")
+
+	ErrCartPricesChanged = errors.New("cart prices changed")
+
+	// ErrCartVersionMismatch is returned by a conditional change if the cart changed since the client read it
+	ErrCartVersionMismatch = errors.New("cart version mismatch")
+
+	// ErrIdempotencyKeyReused is returned if an idempotency key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+	// ErrIdempotencyKeyInProgress is returned if the request with the idempotency key has not completed yet
+	ErrIdempotencyKeyInProgress = errors.New("request with the idempotency key in progress")
 )
+
+// BatchError is returned if some items of a batch are rejected, none of the items are applied then.
+type BatchError struct {
+	Items []ItemError
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d batch items rejected", len(e.Items))
+}
+
+// ItemError is the reason a single batch item is rejected, Index is the position of the item in the batch.
+type ItemError struct {
+	Index     int
+	ProductID uuid.UUID
+	Err       error
+}
+
+// ValidationError lists every invalid field of a request, Field is the path of the field in the request body,
+// e.g. items[0].price.amount, or the name of the path param.
+type ValidationError struct {
+	Violations []Violation
+}
+
+type Violation struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	reasons := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		reasons = append(reasons, v.Field+" "+v.Reason)
+	}
+
+	return "invalid request: " + strings.Join(reasons, ", ")
+}
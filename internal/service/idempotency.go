@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"time"
+)
+
+//go:generate mockery --name=IdempotencyService --structname=MockIdempotencyService --output=. --outpkg=service --filename=idempotency_service_mock.go
+type IdempotencyService interface {
+	// Begin reserves the key for its request and returns nil, unless the request with the key already completed,
+	// then it returns the response to replay.
+	Begin(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotentResponse, error)
+	// Complete stores the response of the request which reserved the key.
+	Complete(ctx context.Context, scope, key string, response domain.IdempotentResponse) error
+	// Release forgets the key of a failed request, so that a retry runs the request again.
+	Release(ctx context.Context, scope, key string) error
+	// PurgeExpired deletes the keys older than the TTL and returns their number.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	store port.IdempotencyStore
+	ttl   time.Duration
+}
+
+// NewIdempotency remembers the keys for ttl, a retry after the ttl runs the request again.
+func NewIdempotency(store port.IdempotencyStore, ttl time.Duration) (IdempotencyService, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("ttl is not positive")
+	}
+
+	return &idempotencyService{store: store, ttl: ttl}, nil
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotentResponse, error) {
+	var v violations
+	v.idempotencyKey(key.Key)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	stored, reserved, err := s.store.ReserveKey(ctx, key, s.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("store.ReserveKey: %w", err)
+	}
+
+	switch {
+	case reserved:
+		return nil, nil
+	case stored.Fingerprint != key.Fingerprint:
+		return nil, ErrIdempotencyKeyReused
+	case stored.Response == nil:
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return stored.Response, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, scope, key string, response domain.IdempotentResponse) error {
+	if err := s.store.SaveResponse(ctx, scope, key, response); err != nil {
+		return fmt.Errorf("store.SaveResponse: %w", err)
+	}
+
+	return nil
+}
+
+func (s *idempotencyService) Release(ctx context.Context, scope, key string) error {
+	if err := s.store.DeleteKey(ctx, scope, key); err != nil {
+		return fmt.Errorf("store.DeleteKey: %w", err)
+	}
+
+	return nil
+}
+
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.store.DeleteExpiredKeys(ctx, s.ttl)
+	if err != nil {
+		return 0, fmt.Errorf("store.DeleteExpiredKeys: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,123 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	domain "github.com/nikolayk812/go-tests/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyService is an autogenerated mock type for the IdempotencyService type
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields: ctx, key
+func (_m *MockIdempotencyService) Begin(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotentResponse, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *domain.IdempotentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.IdempotencyKey) (*domain.IdempotentResponse, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.IdempotencyKey) *domain.IdempotentResponse); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.IdempotentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.IdempotencyKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Complete provides a mock function with given fields: ctx, scope, key, response
+func (_m *MockIdempotencyService) Complete(ctx context.Context, scope string, key string, response domain.IdempotentResponse) error {
+	ret := _m.Called(ctx, scope, key, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.IdempotentResponse) error); ok {
+		r0 = rf(ctx, scope, key, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeExpired provides a mock function with given fields: ctx
+func (_m *MockIdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Release provides a mock function with given fields: ctx, scope, key
+func (_m *MockIdempotencyService) Release(ctx context.Context, scope string, key string) error {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockIdempotencyService creates a new instance of MockIdempotencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIdempotencyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIdempotencyService {
+	mock := &MockIdempotencyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
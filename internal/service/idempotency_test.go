@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"errors"
+	"github.com/brianvoe/gofakeit"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const idempotencyTTL = time.Hour
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	key := domain.IdempotencyKey{
+		Scope:       gofakeit.Username(),
+		Key:         gofakeit.UUID(),
+		Fingerprint: gofakeit.UUID(),
+	}
+
+	response := &domain.IdempotentResponse{StatusCode: http.StatusCreated}
+
+	completed := key
+	completed.Response = response
+
+	inProgress := key
+
+	reused := key
+	reused.Fingerprint = gofakeit.UUID()
+
+	tests := []struct {
+		name      string
+		key       domain.IdempotencyKey
+		mockSetup func(store *port.MockIdempotencyStore)
+		want      *domain.IdempotentResponse
+		wantErr   error
+	}{
+		{
+			name: "reserved",
+			key:  key,
+			mockSetup: func(store *port.MockIdempotencyStore) {
+				store.On("ReserveKey", mock.Anything, key, idempotencyTTL).Return(key, true, nil)
+			},
+		},
+		{
+			name: "completed",
+			key:  key,
+			mockSetup: func(store *port.MockIdempotencyStore) {
+				store.On("ReserveKey", mock.Anything, key, idempotencyTTL).Return(completed, false, nil)
+			},
+			want: response,
+		},
+		{
+			name: "in progress",
+			key:  key,
+			mockSetup: func(store *port.MockIdempotencyStore) {
+				store.On("ReserveKey", mock.Anything, key, idempotencyTTL).Return(inProgress, false, nil)
+			},
+			wantErr: service.ErrIdempotencyKeyInProgress,
+		},
+		{
+			name: "reused with a different request",
+			key:  reused,
+			mockSetup: func(store *port.MockIdempotencyStore) {
+				store.On("ReserveKey", mock.Anything, reused, idempotencyTTL).Return(completed, false, nil)
+			},
+			wantErr: service.ErrIdempotencyKeyReused,
+		},
+		{
+			name:    "key too long",
+			key:     domain.IdempotencyKey{Scope: key.Scope, Key: strings.Repeat("k", 256)},
+			wantErr: errors.New("invalid request: Idempotency-Key must be up to 255 visible ASCII characters"),
+		},
+		{
+			name:    "key with spaces",
+			key:     domain.IdempotencyKey{Scope: key.Scope, Key: "my key"},
+			wantErr: errors.New("invalid request: Idempotency-Key must be up to 255 visible ASCII characters"),
+		},
+		{
+			name: "store error",
+			key:  key,
+			mockSetup: func(store *port.MockIdempotencyStore) {
+				store.On("ReserveKey", mock.Anything, key, idempotencyTTL).
+					Return(domain.IdempotencyKey{}, false, errors.New("db down"))
+			},
+			wantErr: errors.New("store.ReserveKey: db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := new(port.MockIdempotencyStore)
+
+			s, err := service.NewIdempotency(mockStore, idempotencyTTL)
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockStore)
+			}
+
+			actual, err := s.Begin(t.Context(), tt.key)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, actual)
+
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	mockStore := new(port.MockIdempotencyStore)
+	mockStore.On("DeleteExpiredKeys", mock.Anything, idempotencyTTL).Return(int64(3), nil)
+
+	s, err := service.NewIdempotency(mockStore, idempotencyTTL)
+	require.NoError(t, err)
+
+	deleted, err := s.PurgeExpired(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+
+	mockStore.AssertExpectations(t)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"github.com/nikolayk812/go-tests/internal/repository"
+)
+
+const (
+	DefaultOrderPageLimit = 20
+	MaxOrderPageLimit     = 100
+)
+
+//go:generate mockery --name=OrderService --structname=MockOrderService --output=. --outpkg=service --filename=order_service_mock.go
+type OrderService interface {
+	GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error)
+	ListOrders(ctx context.Context, ownerID string, after *domain.OrderCursor, limit int) (domain.OrderPage, error)
+	UpdateStatus(ctx context.Context, orderID uuid.UUID, status domain.OrderStatus, changedBy string) (domain.Order, error)
+	GetStatusHistory(ctx context.Context, orderID uuid.UUID) (domain.Order, []domain.OrderStatusChange, error)
+}
+
+type orderService struct {
+	repo port.OrderRepository
+}
+
+func NewOrder(repo port.OrderRepository) (OrderService, error) {
+	if repo == nil {
+		return nil, errors.New("repo is nil")
+	}
+
+	return &orderService{repo: repo}, nil
+}
+
+func (s *orderService) GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	var v violations
+	v.orderID(orderID)
+	if err := v.err(); err != nil {
+		return domain.Order{}, err
+	}
+
+	order, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return domain.Order{}, ErrOrderNotFound
+		}
+		return domain.Order{}, fmt.Errorf("repo.GetOrder: %w", err)
+	}
+
+	return order, nil
+}
+
+// ListOrders returns a page of the owner's orders, newest first.
+// Non-positive limit falls back to DefaultOrderPageLimit, limit is capped at MaxOrderPageLimit.
+func (s *orderService) ListOrders(ctx context.Context, ownerID string, after *domain.OrderCursor, limit int) (domain.OrderPage, error) {
+	var page domain.OrderPage
+
+	var v violations
+	v.ownerID(ownerID)
+	if err := v.err(); err != nil {
+		return page, err
+	}
+
+	switch {
+	case limit <= 0:
+		limit = DefaultOrderPageLimit
+	case limit > MaxOrderPageLimit:
+		limit = MaxOrderPageLimit
+	}
+
+	// one extra order tells whether there is a next page
+	orders, err := s.repo.ListOrders(ctx, ownerID, after, limit+1)
+	if err != nil {
+		return page, fmt.Errorf("repo.ListOrders: %w", err)
+	}
+
+	if len(orders) > limit {
+		orders = orders[:limit]
+
+		last := orders[len(orders)-1]
+		page.Next = &domain.OrderCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
+	}
+
+	page.Orders = orders
+
+	return page, nil
+}
+
+// UpdateStatus moves the order to the given status if the order lifecycle allows it.
+func (s *orderService) UpdateStatus(ctx context.Context, orderID uuid.UUID, status domain.OrderStatus, changedBy string) (domain.Order, error) {
+	var v violations
+	v.orderID(orderID)
+	if changedBy == "" {
+		v.add("changed_by", "is empty")
+	}
+	if err := v.err(); err != nil {
+		return domain.Order{}, err
+	}
+
+	order, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("s.GetOrder: %w", err)
+	}
+
+	change := domain.OrderStatusChange{
+		From:      order.Status,
+		To:        status,
+		ChangedBy: changedBy,
+	}
+
+	if err := order.TransitionTo(status); err != nil {
+		if errors.Is(err, domain.ErrInvalidStatusTransition) {
+			return domain.Order{}, fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, change.From, change.To)
+		}
+		return domain.Order{}, fmt.Errorf("order.TransitionTo: %w", err)
+	}
+
+	updated, err := s.repo.UpdateOrderStatus(ctx, orderID, change)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("repo.UpdateOrderStatus: %w", err)
+	}
+
+	if !updated {
+		return domain.Order{}, ErrOrderStatusChanged
+	}
+
+	return order, nil
+}
+
+// GetStatusHistory returns the order with its status history, so that the caller can authorize it
+// without loading the order again.
+func (s *orderService) GetStatusHistory(ctx context.Context, orderID uuid.UUID) (domain.Order, []domain.OrderStatusChange, error) {
+	// makes sure a missing order is reported as not found rather than as an empty history
+	order, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return domain.Order{}, nil, fmt.Errorf("s.GetOrder: %w", err)
+	}
+
+	history, err := s.repo.GetOrderStatusHistory(ctx, orderID)
+	if err != nil {
+		return domain.Order{}, nil, fmt.Errorf("repo.GetOrderStatusHistory: %w", err)
+	}
+
+	return order, history, nil
+}
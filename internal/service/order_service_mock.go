@@ -0,0 +1,152 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	domain "github.com/nikolayk812/go-tests/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockOrderService is an autogenerated mock type for the OrderService type
+type MockOrderService struct {
+	mock.Mock
+}
+
+// GetOrder provides a mock function with given fields: ctx, orderID
+func (_m *MockOrderService) GetOrder(ctx context.Context, orderID uuid.UUID) (domain.Order, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Order, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Order); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatusHistory provides a mock function with given fields: ctx, orderID
+func (_m *MockOrderService) GetStatusHistory(ctx context.Context, orderID uuid.UUID) (domain.Order, []domain.OrderStatusChange, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatusHistory")
+	}
+
+	var r0 domain.Order
+	var r1 []domain.OrderStatusChange
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (domain.Order, []domain.OrderStatusChange, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) domain.Order); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) []domain.OrderStatusChange); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]domain.OrderStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, orderID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListOrders provides a mock function with given fields: ctx, ownerID, after, limit
+func (_m *MockOrderService) ListOrders(ctx context.Context, ownerID string, after *domain.OrderCursor, limit int) (domain.OrderPage, error) {
+	ret := _m.Called(ctx, ownerID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrders")
+	}
+
+	var r0 domain.OrderPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderCursor, int) (domain.OrderPage, error)); ok {
+		return rf(ctx, ownerID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderCursor, int) domain.OrderPage); ok {
+		r0 = rf(ctx, ownerID, after, limit)
+	} else {
+		r0 = ret.Get(0).(domain.OrderPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.OrderCursor, int) error); ok {
+		r1 = rf(ctx, ownerID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, orderID, status, changedBy
+func (_m *MockOrderService) UpdateStatus(ctx context.Context, orderID uuid.UUID, status domain.OrderStatus, changedBy string) (domain.Order, error) {
+	ret := _m.Called(ctx, orderID, status, changedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OrderStatus, string) (domain.Order, error)); ok {
+		return rf(ctx, orderID, status, changedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OrderStatus, string) domain.Order); ok {
+		r0 = rf(ctx, orderID, status, changedBy)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.OrderStatus, string) error); ok {
+		r1 = rf(ctx, orderID, status, changedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockOrderService creates a new instance of MockOrderService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOrderService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOrderService {
+	mock := &MockOrderService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,277 @@
+package service_test
+
+import (
+	"errors"
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"github.com/nikolayk812/go-tests/internal/port"
+	"github.com/nikolayk812/go-tests/internal/repository"
+	"github.com/nikolayk812/go-tests/internal/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestOrderService_GetOrder(t *testing.T) {
+	order := fakeOrder(time.Now())
+
+	tests := []struct {
+		name      string
+		orderID   uuid.UUID
+		mockSetup func(repo *port.MockOrderRepository)
+		wantErr   error
+	}{
+		{
+			name:    "success",
+			orderID: order.ID,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("GetOrder", mock.Anything, order.ID).Return(order, nil)
+			},
+		},
+		{
+			name:    "orderID is empty",
+			orderID: uuid.Nil,
+			wantErr: errors.New("invalid request: order_id is empty"),
+		},
+		{
+			name:    "not found",
+			orderID: order.ID,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("GetOrder", mock.Anything, order.ID).Return(domain.Order{}, repository.ErrOrderNotFound)
+			},
+			wantErr: service.ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(port.MockOrderRepository)
+
+			s, err := service.NewOrder(mockRepo)
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			actual, err := s.GetOrder(t.Context(), tt.orderID)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, order, actual)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_ListOrders(t *testing.T) {
+	ownerID := gofakeit.UUID()
+
+	now := time.Now()
+	order1 := fakeOrder(now)
+	order2 := fakeOrder(now.Add(-time.Minute))
+	order3 := fakeOrder(now.Add(-2 * time.Minute))
+
+	after := &domain.OrderCursor{CreatedAt: now.Add(time.Minute), ID: uuid.MustParse(gofakeit.UUID())}
+
+	tests := []struct {
+		name      string
+		ownerID   string
+		after     *domain.OrderCursor
+		limit     int
+		mockSetup func(repo *port.MockOrderRepository)
+		wantPage  domain.OrderPage
+		wantErr   error
+	}{
+		{
+			name:    "last page",
+			ownerID: ownerID,
+			limit:   3,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("ListOrders", mock.Anything, ownerID, (*domain.OrderCursor)(nil), 4).
+					Return([]domain.Order{order1, order2, order3}, nil)
+			},
+			wantPage: domain.OrderPage{Orders: []domain.Order{order1, order2, order3}},
+		},
+		{
+			name:    "next page exists",
+			ownerID: ownerID,
+			after:   after,
+			limit:   2,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("ListOrders", mock.Anything, ownerID, after, 3).
+					Return([]domain.Order{order1, order2, order3}, nil)
+			},
+			wantPage: domain.OrderPage{
+				Orders: []domain.Order{order1, order2},
+				Next:   &domain.OrderCursor{CreatedAt: order2.CreatedAt, ID: order2.ID},
+			},
+		},
+		{
+			name:    "default limit",
+			ownerID: ownerID,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("ListOrders", mock.Anything, ownerID, (*domain.OrderCursor)(nil), service.DefaultOrderPageLimit+1).
+					Return(nil, nil)
+			},
+		},
+		{
+			name:    "limit is capped",
+			ownerID: ownerID,
+			limit:   service.MaxOrderPageLimit + 1,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("ListOrders", mock.Anything, ownerID, (*domain.OrderCursor)(nil), service.MaxOrderPageLimit+1).
+					Return(nil, nil)
+			},
+		},
+		{
+			name:    "ownerID is empty",
+			wantErr: errors.New("invalid request: owner_id is empty"),
+		},
+		{
+			name:    "unexpected error from repo",
+			ownerID: ownerID,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("ListOrders", mock.Anything, ownerID, (*domain.OrderCursor)(nil), service.DefaultOrderPageLimit+1).
+					Return(nil, errors.New("unexpected error"))
+			},
+			wantErr: errors.New("repo.ListOrders: unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(port.MockOrderRepository)
+
+			s, err := service.NewOrder(mockRepo)
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			page, err := s.ListOrders(t.Context(), tt.ownerID, tt.after, tt.limit)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPage, page)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_UpdateStatus(t *testing.T) {
+	order := fakeOrder(time.Now())
+	order.Status = domain.OrderStatusPending
+
+	paidOrder := order
+	paidOrder.Status = domain.OrderStatusPaid
+
+	changedBy := gofakeit.Username()
+
+	change := domain.OrderStatusChange{
+		From:      domain.OrderStatusPending,
+		To:        domain.OrderStatusPaid,
+		ChangedBy: changedBy,
+	}
+
+	tests := []struct {
+		name      string
+		status    domain.OrderStatus
+		changedBy string
+		mockSetup func(repo *port.MockOrderRepository)
+		wantOrder domain.Order
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			status:    domain.OrderStatusPaid,
+			changedBy: changedBy,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("GetOrder", mock.Anything, order.ID).Return(order, nil)
+				repo.On("UpdateOrderStatus", mock.Anything, order.ID, change).Return(true, nil)
+			},
+			wantOrder: paidOrder,
+		},
+		{
+			name:    "changedBy is empty",
+			status:  domain.OrderStatusPaid,
+			wantErr: errors.New("invalid request: changed_by is empty"),
+		},
+		{
+			name:      "order not found",
+			status:    domain.OrderStatusPaid,
+			changedBy: changedBy,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("GetOrder", mock.Anything, order.ID).Return(domain.Order{}, repository.ErrOrderNotFound)
+			},
+			wantErr: service.ErrOrderNotFound,
+		},
+		{
+			name:      "illegal transition",
+			status:    domain.OrderStatusDelivered,
+			changedBy: changedBy,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("GetOrder", mock.Anything, order.ID).Return(order, nil)
+			},
+			wantErr: service.ErrInvalidStatusTransition,
+		},
+		{
+			name:      "status changed concurrently",
+			status:    domain.OrderStatusPaid,
+			changedBy: changedBy,
+			mockSetup: func(repo *port.MockOrderRepository) {
+				repo.On("GetOrder", mock.Anything, order.ID).Return(order, nil)
+				repo.On("UpdateOrderStatus", mock.Anything, order.ID, change).Return(false, nil)
+			},
+			wantErr: service.ErrOrderStatusChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(port.MockOrderRepository)
+
+			s, err := service.NewOrder(mockRepo)
+			require.NoError(t, err)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			actual, err := s.UpdateStatus(t.Context(), order.ID, tt.status, tt.changedBy)
+			if tt.wantErr != nil {
+				require.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOrder, actual)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func fakeOrder(createdAt time.Time) domain.Order {
+	item := fakeCartItem()
+
+	return domain.Order{
+		ID:      uuid.MustParse(gofakeit.UUID()),
+		OwnerID: gofakeit.UUID(),
+		Items: []domain.OrderItem{
+			{ProductID: item.ProductID, Price: item.Price, CreatedAt: createdAt},
+		},
+		CreatedAt: createdAt,
+	}
+}
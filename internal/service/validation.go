@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/go-tests/internal/domain"
+	"golang.org/x/text/currency"
+	"regexp"
+)
+
+// validOwnerID limits owner IDs to the owner_id column size and to characters safe in a URL path.
+var validOwnerID = regexp.MustCompile(`^[A-Za-z0-9._@-]{1,255}$`)
+
+// validIdempotencyKey limits idempotency keys to the key column size and to visible ASCII characters,
+// e.g. a UUID generated by the client.
+var validIdempotencyKey = regexp.MustCompile(`^[\x21-\x7E]{1,255}$`)
+
+// UnsupportedCurrency stands for a currency code which is not an ISO 4217 code, so that the code is reported
+// with the other invalid fields. XTS is reserved for testing, no price is in it.
+var UnsupportedCurrency = currency.XTS
+
+// violations collects invalid fields, so that a request is rejected with all of them at once.
+type violations []Violation
+
+func (v *violations) add(field, reason string) {
+	*v = append(*v, Violation{Field: field, Reason: reason})
+}
+
+// err returns *ValidationError if there are violations.
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: v}
+}
+
+func (v *violations) ownerID(ownerID string) {
+	switch {
+	case ownerID == "":
+		v.add("owner_id", "is empty")
+	case !validOwnerID.MatchString(ownerID):
+		v.add("owner_id", "must be up to 255 letters, digits, '.', '_', '@' or '-'")
+	}
+}
+
+func (v *violations) idempotencyKey(key string) {
+	if !validIdempotencyKey.MatchString(key) {
+		v.add("Idempotency-Key", "must be up to 255 visible ASCII characters")
+	}
+}
+
+func (v *violations) productID(field string, productID uuid.UUID) {
+	if productID == uuid.Nil {
+		v.add(field, "is empty")
+	}
+}
+
+func (v *violations) orderID(orderID uuid.UUID) {
+	if orderID == uuid.Nil {
+		v.add("order_id", "is empty")
+	}
+}
+
+func (v *violations) quantity(field string, quantity int) {
+	if quantity < 0 {
+		v.add(field, "is negative")
+	}
+}
+
+// currency reports whether the currency is set and supported.
+func (v *violations) currency(field string, unit currency.Unit) bool {
+	switch unit {
+	case currency.Unit{}:
+		v.add(field, "is empty")
+		return false
+	case UnsupportedCurrency:
+		v.add(field, "is not a supported currency")
+		return false
+	}
+
+	return true
+}
+
+// money checks the currency, and that the amount is positive and has no more decimal places
+// than the currency minor units.
+func (v *violations) money(field string, money domain.Money) {
+	if !v.currency(field+".currency", money.Currency) {
+		return
+	}
+
+	if !money.Amount.IsPositive() {
+		v.add(field+".amount", "must be positive")
+	}
+
+	scale := money.Scale()
+	if !money.Amount.Equal(money.Amount.Round(int32(scale))) {
+		v.add(field+".amount", fmt.Sprintf("has more than %d decimal places for %s", scale, money.Currency))
+	}
+}
+
+// cartItem validates the item to add, the price is optional.
+func (v *violations) cartItem(prefix string, item domain.CartItem) {
+	v.productID(prefix+"product_id", item.ProductID)
+	v.quantity(prefix+"quantity", item.Quantity)
+
+	if item.Price.Currency != (currency.Unit{}) {
+		v.money(prefix+"price", item.Price)
+	}
+}
@@ -8,11 +8,58 @@ import (
 type Cart struct {
 	OwnerID string     `json:"owner_id"`
 	Items   []CartItem `json:"items"`
+	Totals  []Money    `json:"totals"` // one per currency
+
+	// DisplayTotal is present if a display currency is requested
+	DisplayTotal *ConvertedTotal `json:"display_total,omitempty"`
 }
 
 type CartItem struct {
-	ProductID uuid.UUID `json:"product_id" binding:"required"`
-	Price     Money     `json:"price" binding:"required"`
+	ProductID uuid.UUID `json:"product_id"`
+	Price     Money     `json:"price"` // optional on input, the catalog price is used
+	Quantity  int       `json:"quantity"`
 
 	CreatedAt time.Time `json:"created_at"`
+
+	// PriceChange is present if the catalog price differs from the item price
+	PriceChange *PriceChange `json:"price_change,omitempty"`
+}
+
+type CartItemBatch struct {
+	Items []CartItem `json:"items"`
+}
+
+// CartItemError describes why an item of a batch is rejected, Index is the position of the item in the batch.
+type CartItemError struct {
+	Index     int       `json:"index"`
+	ProductID uuid.UUID `json:"product_id"`
+	Type      string    `json:"type"` // one of the problem types
+	Error     string    `json:"error"`
+}
+
+type CartItemIDs struct {
+	ProductIDs []uuid.UUID `json:"product_ids"`
+}
+
+type CartItemsDeleted struct {
+	Deleted int `json:"deleted"`
+}
+
+type PriceChange struct {
+	OldPrice  Money     `json:"old_price"`
+	NewPrice  Money     `json:"new_price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type Reprice struct {
+	Items []ItemPrice `json:"items"`
+}
+
+type ItemPrice struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Price     Money     `json:"price"`
+}
+
+type CartItemQuantity struct {
+	Quantity *int `json:"quantity" binding:"required"` // tells a missing quantity from zero, which removes the item
 }
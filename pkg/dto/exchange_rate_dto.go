@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type ExchangeRate struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Rate      decimal.Decimal `json:"rate"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type ConvertedTotal struct {
+	Total Money          `json:"total"`
+	Rates []ExchangeRate `json:"rates"`
+}
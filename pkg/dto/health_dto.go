@@ -0,0 +1,21 @@
+package dto
+
+// Health statuses, a service which is down or draining responds with 503.
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDown     = "down"
+	HealthStatusDraining = "draining"
+)
+
+type Health struct {
+	Status string `json:"status"`
+
+	// Checks are the dependency checks of the readiness probe by name
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
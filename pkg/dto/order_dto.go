@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Order struct {
+	ID      uuid.UUID   `json:"id"`
+	OwnerID string      `json:"owner_id"`
+	Status  string      `json:"status"`
+	Items   []OrderItem `json:"items"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrderItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Price     Money     `json:"price"`
+	Quantity  int       `json:"quantity"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrderList struct {
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// OrderStatusUpdate is sent by admins, the status change is recorded as made by the subject of the token.
+type OrderStatusUpdate struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type OrderStatusChange struct {
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
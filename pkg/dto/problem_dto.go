@@ -0,0 +1,50 @@
+package dto
+
+// ProblemContentType is the media type of Problem responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem types are stable, clients can rely on them rather than on the title or detail.
+const (
+	ProblemTypeBadRequest               = "/problems/bad-request"
+	ProblemTypeInternal                 = "/problems/internal"
+	ProblemTypeValidation               = "/problems/validation"
+	ProblemTypeUnauthorized             = "/problems/unauthorized"
+	ProblemTypeForbidden                = "/problems/forbidden"
+	ProblemTypeCartDuplicateItem        = "/problems/cart-duplicate-item"
+	ProblemTypeCartItemNotFound         = "/problems/cart-item-not-found"
+	ProblemTypeCartItemsRejected        = "/problems/cart-items-rejected"
+	ProblemTypeCartEmpty                = "/problems/cart-empty"
+	ProblemTypeCartPricesChanged        = "/problems/cart-prices-changed"
+	ProblemTypeCartVersionMismatch      = "/problems/cart-version-mismatch"
+	ProblemTypeProductNotFound          = "/problems/product-not-found"
+	ProblemTypePriceMismatch            = "/problems/price-mismatch"
+	ProblemTypeOrderNotFound            = "/problems/order-not-found"
+	ProblemTypeInvalidStatusTransition  = "/problems/invalid-status-transition"
+	ProblemTypeOrderStatusChanged       = "/problems/order-status-changed"
+	ProblemTypeExchangeRateNotFound     = "/problems/exchange-rate-not-found"
+	ProblemTypeIdempotencyKeyReused     = "/problems/idempotency-key-reused"
+	ProblemTypeIdempotencyKeyInProgress = "/problems/idempotency-key-in-progress"
+)
+
+// Problem is an error response body as defined by RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	RequestID string `json:"request_id,omitempty"`
+
+	// Items is present if items of a batch are rejected
+	Items []CartItemError `json:"items,omitempty"`
+
+	// InvalidParams is present if request fields are invalid
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam is an invalid request field, Name is the path of the field, e.g. items[0].price.amount.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}